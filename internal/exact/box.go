@@ -0,0 +1,278 @@
+package exact
+
+import (
+	"log"
+	"math/bits"
+)
+
+// Box is an axis-aligned rectangle of the points (x,y) with
+// XMin <= x < XMax and YMin <= y < YMax.
+type Box struct {
+	XMin, XMax int64
+	YMin, YMax int64
+}
+
+// empty reports whether b holds no points.
+func (b Box) empty() bool {
+	return b.XMin >= b.XMax || b.YMin >= b.YMax
+}
+
+// SegmentOverlaps indicates whether the closed segment (x1,y1)<->(x2,y2)
+// overlaps, and is fully contained by, the Box b.
+func SegmentOverlaps(x1, y1, x2, y2 int64, b Box) (bool, bool) {
+	if b.empty() {
+		return false, false // nothing overlaps an empty box
+	}
+
+	oi1 := octothorpeInfo(b, x1, y1)
+	oi2 := octothorpeInfo(b, x2, y2)
+	if oi1 == (row2|col2) || oi2 == (row2|col2) {
+		// at least one point is in the box
+		return true, oi1&oi2 == row2|col2
+	}
+
+	bothOiAnded := oi1 & oi2
+
+	switch bothOiAnded & rowBits {
+	case row1: // both points in top row
+		return false, false // too high
+	case row3: // both points in bottom row
+		return false, false // too low
+	case row2: // both points in center row
+		if (oi1|oi2)&colBits == col1|col3 {
+			return true, false // line crosses left/right through box
+		}
+	}
+
+	switch bothOiAnded & colBits {
+	case col1:
+		return false, false // both points in col1 (too left)
+	case col3:
+		return false, false // both points in col3 (too right)
+	case col2:
+		if (oi1|oi2)&rowBits == row1|row3 {
+			return true, false // line crosses top/bottom through box
+		}
+	}
+
+	// If we got here, the line must have:
+	//  - One endpoint in the center row and one in the max/min column
+	//  - One endpoint in the center column and one in the max/min row
+	// No shortcuts available, and neither endpoint is in the box. Clip the
+	// line against the box.
+	tr := tRange{
+		lo: fraction{num: 0, den: 1},
+		hi: fraction{num: 1, den: 1},
+	}
+	tr.clip(x1, x2, b.XMin, b.XMax)
+	tr.clip(y1, y2, b.YMin, b.YMax)
+
+	return !tr.isEmpty(), false
+}
+
+const (
+	col1    = 1 << 0
+	col2    = 1 << 1
+	col3    = 1 << 2
+	colBits = col1 | col2 | col3
+
+	row1    = 1 << 3
+	row2    = 1 << 4
+	row3    = 1 << 5
+	rowBits = row1 | row2 | row3
+)
+
+// octothorpeInfo takes an (x,y) coordinate pair returns a byte with exactly two
+// bits set. The lower bit indicates the coordinate pair's relationship with the
+// box's x-axis limits. The higher bit indicates the coordinate pair's
+// relationship with the box's y-axis limits.
+//
+// One of these nine values will be returned: [9, 10, 12, 17, 18, 20, 33, 34, 36]
+//
+//	  col1                 col2                 col3
+//	00000001    x-min    00000010    x-max    00000100
+//	              |                    |
+//	00001001      |      00001010      |      00001100       00001000
+//	    9         |         10         |         12            row1
+//	              |                    |
+//
+// --------------------+--------------------+-------------------- y-max
+//
+//	              |                    |
+//	00010001      |      00010010      |      00010100       00010000
+//	   17         |         18         |         20            row2
+//	              |                    |
+//
+// --------------------+--------------------+-------------------- y-min
+//
+//	              |                    |
+//	00100001      |      00100010      |      00100100       00100000
+//	   33         |         34         |         36            row3
+//	              |                    |
+func octothorpeInfo(b Box, x, y int64) byte {
+	var result byte
+	switch {
+	case x >= b.XMax:
+		result = col3
+	case x >= b.XMin:
+		result = col2
+	case x < b.XMin:
+		result = col1
+	}
+
+	switch {
+	case y >= b.YMax:
+		return result | row1
+	case y >= b.YMin:
+		return result | row2
+	case y < b.YMin:
+		return result | row3
+	}
+
+	if result&(col1|col2|col3) == 0 {
+		log.Panic("impossible situation in octothorpeInfo x-axis")
+	}
+
+	if result&(row1|row2|row3) == 0 {
+		log.Panic("impossible situation in octothorpeInfo y-axis")
+	}
+
+	log.Panic("impossible situation in octothorpeInfo")
+	return 0
+}
+
+// fraction is an exact rational value: ±num/den. den is always non-zero.
+// Numerator and denominator are unsigned magnitudes because the difference
+// between two int64 coordinates may need all 64 bits.
+type fraction struct {
+	neg bool
+	num uint64
+	den uint64
+}
+
+// cmp returns -1, 0 or +1 as f is less than, equal to or greater than g.
+// Cross multiplication is done with 128-bit products, so no precision is
+// lost.
+func (f fraction) cmp(g fraction) int {
+	fSign := sign(f)
+	gSign := sign(g)
+	switch {
+	case fSign < gSign:
+		return -1
+	case fSign > gSign:
+		return 1
+	case fSign == 0:
+		return 0
+	}
+
+	// same sign, non-zero: compare magnitudes f.num/f.den vs g.num/g.den
+	lHi, lLo := bits.Mul64(f.num, g.den)
+	rHi, rLo := bits.Mul64(g.num, f.den)
+
+	var magCmp int
+	switch {
+	case lHi < rHi, lHi == rHi && lLo < rLo:
+		magCmp = -1
+	case lHi == rHi && lLo == rLo:
+		magCmp = 0
+	default:
+		magCmp = 1
+	}
+
+	return magCmp * fSign
+}
+
+func sign(f fraction) int {
+	switch {
+	case f.num == 0:
+		return 0
+	case f.neg:
+		return -1
+	}
+	return 1
+}
+
+// tRange is a range of the segment parameter t, where t=0 is the first
+// endpoint and t=1 is the second. Each end of the range may be open or
+// closed. A ray's range is unbounded above until clipped, in which case hi
+// is unused.
+type tRange struct {
+	lo, hi         fraction
+	loOpen, hiOpen bool
+	unbounded      bool
+	empty          bool
+}
+
+func (r *tRange) raiseLo(v fraction, open bool) {
+	switch v.cmp(r.lo) {
+	case 1:
+		r.lo, r.loOpen = v, open
+	case 0:
+		r.loOpen = r.loOpen || open
+	}
+}
+
+func (r *tRange) lowerHi(v fraction, open bool) {
+	if r.unbounded {
+		r.hi, r.hiOpen, r.unbounded = v, open, false
+		return
+	}
+
+	switch v.cmp(r.hi) {
+	case -1:
+		r.hi, r.hiOpen = v, open
+	case 0:
+		r.hiOpen = r.hiOpen || open
+	}
+}
+
+// clip restricts r to the values of t for which p0 + t*(p1-p0) falls within
+// the half-open range [min, max).
+func (r *tRange) clip(p0, p1, min, max int64) {
+	dNeg, dMag := Difference(p1, p0)
+	r.clipDir(p0, dNeg, dMag, min, max)
+}
+
+// clipDir restricts r to the values of t for which p0 + t*d falls within the
+// half-open range [min, max), where d is given as a sign and magnitude.
+func (r *tRange) clipDir(p0 int64, dNeg bool, dMag uint64, min, max int64) {
+	if dMag == 0 {
+		if p0 < min || p0 >= max {
+			r.empty = true
+		}
+		return
+	}
+
+	// t at which p0 + t*d crosses min and max
+	minNeg, minMag := Difference(min, p0)
+	maxNeg, maxMag := Difference(max, p0)
+	atMin := fraction{neg: minNeg != dNeg, num: minMag, den: dMag}
+	atMax := fraction{neg: maxNeg != dNeg, num: maxMag, den: dMag}
+
+	if dNeg {
+		// moving toward min: t <= atMin (closed), t > atMax (open)
+		r.lowerHi(atMin, false)
+		r.raiseLo(atMax, true)
+	} else {
+		// moving toward max: t >= atMin (closed), t < atMax (open)
+		r.raiseLo(atMin, false)
+		r.lowerHi(atMax, true)
+	}
+}
+
+func (r *tRange) isEmpty() bool {
+	switch {
+	case r.empty:
+		return true
+	case r.unbounded:
+		return false
+	}
+
+	switch r.lo.cmp(r.hi) {
+	case -1:
+		return false
+	case 0:
+		return r.loOpen || r.hiOpen
+	}
+	return true
+}
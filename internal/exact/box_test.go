@@ -0,0 +1,56 @@
+package exact
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSegmentOverlaps(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		x1, y1, x2, y2 int64
+		box            Box
+		expOverlap     bool
+		expContained   bool
+	}
+
+	b := Box{XMin: 0, XMax: 10, YMin: 0, YMax: 10}
+
+	testCases := map[string]testCase{
+		"inside":         {x1: 1, y1: 1, x2: 9, y2: 9, box: b, expOverlap: true, expContained: true},
+		"one_end_inside": {x1: 5, y1: 5, x2: 20, y2: 20, box: b, expOverlap: true},
+		"crosses":        {x1: -5, y1: 5, x2: 15, y2: 5, box: b, expOverlap: true},
+		"clips_corner":   {x1: -1, y1: 5, x2: 5, y2: -1, box: b, expOverlap: true},
+		"misses_corner":  {x1: -1, y1: 0, x2: 0, y2: -1, box: b},
+		"touches_max":    {x1: 10, y1: 0, x2: 10, y2: 9, box: b},
+		"touches_min":    {x1: 0, y1: -5, x2: 0, y2: 5, box: b, expOverlap: true},
+		"empty_box":      {x1: 0, y1: 0, x2: 1, y2: 1, box: Box{XMin: 0, XMax: 0, YMin: 0, YMax: 10}},
+		"above":          {x1: -5, y1: 15, x2: 15, y2: 15, box: b},
+		"huge_near_miss": {
+			x1: math.MinInt64, y1: math.MinInt64 + 1, x2: math.MaxInt64 - 1, y2: math.MaxInt64,
+			box: Box{XMin: 0, XMax: 1, YMin: -1, YMax: 0},
+		},
+		"huge_hit": {
+			x1: math.MinInt64, y1: math.MinInt64, x2: math.MaxInt64, y2: math.MaxInt64,
+			box:        Box{XMin: 0, XMax: 1, YMin: 0, YMax: 1},
+			expOverlap: true,
+		},
+	}
+
+	for tName, tCase := range testCases {
+		t.Run(tName, func(t *testing.T) {
+			t.Parallel()
+
+			overlap, contained := SegmentOverlaps(tCase.x1, tCase.y1, tCase.x2, tCase.y2, tCase.box)
+			require.Equal(t, tCase.expOverlap, overlap)
+			require.Equal(t, tCase.expContained, contained)
+
+			overlap, contained = SegmentOverlaps(tCase.x2, tCase.y2, tCase.x1, tCase.y1, tCase.box)
+			require.Equal(t, tCase.expOverlap, overlap)
+			require.Equal(t, tCase.expContained, contained)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+// Package exact holds the integer geometry shared by the tdqt and objects
+// packages. Every test is made without rounding, so results hold across the
+// whole int64 plane.
+package exact
+
+import "math/bits"
+
+// Difference returns a-b as a sign and magnitude, without overflow.
+func Difference(a, b int64) (bool, uint64) {
+	if a >= b {
+		return false, uint64(a) - uint64(b)
+	}
+	return true, uint64(b) - uint64(a)
+}
+
+// int128 is a sign-magnitude 128-bit integer, wide enough to hold the
+// product of two coordinate differences.
+type int128 struct {
+	neg bool
+	hi  uint64
+	lo  uint64
+}
+
+// mul returns the exact product of two sign-magnitude values.
+func mul(aNeg bool, a uint64, bNeg bool, b uint64) int128 {
+	hi, lo := bits.Mul64(a, b)
+	return int128{neg: aNeg != bNeg && (hi|lo) != 0, hi: hi, lo: lo}
+}
+
+// cmp returns -1, 0 or +1 as x is less than, equal to or greater than y.
+func (x int128) cmp(y int128) int {
+	switch {
+	case x.neg && !y.neg:
+		return -1
+	case !x.neg && y.neg:
+		return 1
+	}
+
+	var magCmp int
+	switch {
+	case x.hi < y.hi, x.hi == y.hi && x.lo < y.lo:
+		magCmp = -1
+	case x.hi == y.hi && x.lo == y.lo:
+		magCmp = 0
+	default:
+		magCmp = 1
+	}
+
+	if x.neg {
+		return -magCmp
+	}
+	return magCmp
+}
+
+// Orientation returns +1 if the points a, b and c make a counterclockwise
+// turn, -1 if they make a clockwise turn, and 0 if they are collinear. The
+// cross product is computed exactly with 128-bit arithmetic.
+func Orientation(ax, ay, bx, by, cx, cy int64) int {
+	dx1Neg, dx1 := Difference(bx, ax)
+	dy1Neg, dy1 := Difference(by, ay)
+	dx2Neg, dx2 := Difference(cx, ax)
+	dy2Neg, dy2 := Difference(cy, ay)
+
+	// (b-a) x (c-a) = dx1*dy2 - dy1*dx2
+	return mul(dx1Neg, dx1, dy2Neg, dy2).cmp(mul(dy1Neg, dy1, dx2Neg, dx2))
+}
+
+// SegmentsIntersect determines whether the closed segments
+// (ax1,ay1)<->(ax2,ay2) and (bx1,by1)<->(bx2,by2) share any point. Touching
+// endpoints and collinear overlaps count as intersections.
+func SegmentsIntersect(ax1, ay1, ax2, ay2, bx1, by1, bx2, by2 int64) bool {
+	o1 := Orientation(ax1, ay1, ax2, ay2, bx1, by1)
+	o2 := Orientation(ax1, ay1, ax2, ay2, bx2, by2)
+	o3 := Orientation(bx1, by1, bx2, by2, ax1, ay1)
+	o4 := Orientation(bx1, by1, bx2, by2, ax2, ay2)
+
+	if o1*o2 < 0 && o3*o4 < 0 {
+		return true // proper crossing
+	}
+
+	// An endpoint of one segment lying on the other.
+	return o1 == 0 && onSegment(ax1, ay1, ax2, ay2, bx1, by1) ||
+		o2 == 0 && onSegment(ax1, ay1, ax2, ay2, bx2, by2) ||
+		o3 == 0 && onSegment(bx1, by1, bx2, by2, ax1, ay1) ||
+		o4 == 0 && onSegment(bx1, by1, bx2, by2, ax2, ay2)
+}
+
+// onSegment reports whether the point (x,y), which must be collinear with the
+// segment (x1,y1)<->(x2,y2), lies on it.
+func onSegment(x1, y1, x2, y2, x, y int64) bool {
+	return min(x1, x2) <= x && x <= max(x1, x2) && min(y1, y2) <= y && y <= max(y1, y2)
+}
@@ -0,0 +1,108 @@
+package exact
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDifference(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		a, b    int64
+		expNeg  bool
+		expDiff uint64
+	}
+
+	testCases := map[string]testCase{
+		"zero":     {a: 5, b: 5},
+		"positive": {a: 5, b: 2, expDiff: 3},
+		"negative": {a: 2, b: 5, expNeg: true, expDiff: 3},
+		"full_range": {
+			a: math.MaxInt64, b: math.MinInt64,
+			expDiff: math.MaxUint64,
+		},
+		"full_range_negative": {
+			a: math.MinInt64, b: math.MaxInt64,
+			expNeg: true, expDiff: math.MaxUint64,
+		},
+	}
+
+	for tName, tCase := range testCases {
+		t.Run(tName, func(t *testing.T) {
+			t.Parallel()
+
+			neg, diff := Difference(tCase.a, tCase.b)
+			require.Equal(t, tCase.expNeg, neg)
+			require.Equal(t, tCase.expDiff, diff)
+		})
+	}
+}
+
+func TestOrientation(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		ax, ay, bx, by, cx, cy int64
+		exp                    int
+	}
+
+	testCases := map[string]testCase{
+		"counterclockwise": {bx: 10, cx: 10, cy: 10, exp: 1},
+		"clockwise":        {bx: 10, cx: 10, cy: -10, exp: -1},
+		"collinear":        {bx: 10, by: 10, cx: 20, cy: 20},
+		"huge_collinear": {
+			ax: math.MinInt64, ay: math.MinInt64,
+			bx: math.MaxInt64, by: math.MaxInt64,
+			cx: 0, cy: 0,
+		},
+		"huge_off_by_one": {
+			ax: math.MinInt64, ay: math.MinInt64,
+			bx: math.MaxInt64, by: math.MaxInt64,
+			cx: 0, cy: 1,
+			exp: 1,
+		},
+	}
+
+	for tName, tCase := range testCases {
+		t.Run(tName, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tCase.exp, Orientation(tCase.ax, tCase.ay, tCase.bx, tCase.by, tCase.cx, tCase.cy))
+		})
+	}
+}
+
+func TestSegmentsIntersect(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		a, b [4]int64
+		exp  bool
+	}
+
+	testCases := map[string]testCase{
+		"crossing":          {a: [4]int64{0, 0, 10, 10}, b: [4]int64{0, 10, 10, 0}, exp: true},
+		"touching_endpoint": {a: [4]int64{0, 0, 10, 10}, b: [4]int64{10, 10, 20, 0}, exp: true},
+		"collinear_overlap": {a: [4]int64{0, 0, 10, 10}, b: [4]int64{5, 5, 20, 20}, exp: true},
+		"collinear_apart":   {a: [4]int64{0, 0, 10, 10}, b: [4]int64{11, 11, 20, 20}},
+		"parallel":          {a: [4]int64{0, 0, 10, 0}, b: [4]int64{0, 1, 10, 1}},
+		"near_miss":         {a: [4]int64{0, 0, 10, 10}, b: [4]int64{6, 4, 10, 0}},
+		"huge_near_miss": {
+			a: [4]int64{math.MinInt64, math.MinInt64, math.MaxInt64, math.MaxInt64},
+			b: [4]int64{0, 1, 0, math.MaxInt64},
+		},
+	}
+
+	for tName, tCase := range testCases {
+		t.Run(tName, func(t *testing.T) {
+			t.Parallel()
+
+			a, b := tCase.a, tCase.b
+			require.Equal(t, tCase.exp, SegmentsIntersect(a[0], a[1], a[2], a[3], b[0], b[1], b[2], b[3]))
+			require.Equal(t, tCase.exp, SegmentsIntersect(b[0], b[1], b[2], b[3], a[0], a[1], a[2], a[3]))
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package exact
+
+import (
+	"math"
+	"math/big"
+)
+
+// RayOverlaps determines whether any point on the ray from (x,y) in the
+// direction (dx,dy) falls within the Box b. A zero direction leaves only the
+// point (x,y) itself.
+func RayOverlaps(x, y, dx, dy int64, b Box) bool {
+	if b.empty() {
+		return false
+	}
+
+	tr := tRange{lo: fraction{num: 0, den: 1}, unbounded: true}
+	dxNeg, dxMag := Difference(dx, 0)
+	dyNeg, dyMag := Difference(dy, 0)
+	tr.clipDir(x, dxNeg, dxMag, b.XMin, b.XMax)
+	tr.clipDir(y, dyNeg, dyMag, b.YMin, b.YMax)
+
+	return !tr.isEmpty()
+}
+
+// RaySegmentDistance determines whether the ray from (x,y) in the direction
+// (dx,dy) meets the closed segment (x1,y1)<->(x2,y2), and the distance from
+// (x,y) to the nearest point at which it does. Whether they meet is decided
+// exactly. Only the distance is rounded.
+func RaySegmentDistance(x, y, dx, dy, x1, y1, x2, y2 int64) (float64, bool) {
+	bigInt := func(v int64) *big.Int { return big.NewInt(v) }
+	sub := func(a, b int64) *big.Int { return new(big.Int).Sub(bigInt(a), bigInt(b)) }
+	cross := func(ax, ay, bx, by *big.Int) *big.Int {
+		l := new(big.Int).Mul(ax, by)
+		return l.Sub(l, new(big.Int).Mul(ay, bx))
+	}
+	dot := func(ax, ay, bx, by *big.Int) *big.Int {
+		l := new(big.Int).Mul(ax, bx)
+		return l.Add(l, new(big.Int).Mul(ay, by))
+	}
+
+	dX, dY := bigInt(dx), bigInt(dy)
+	eX, eY := sub(x2, x1), sub(y2, y1) // along the segment
+	wX, wY := sub(x1, x), sub(y1, y)   // from the origin to the segment's start
+	length := math.Hypot(float64(dx), float64(dy))
+
+	// Solve origin + t*d = start + s*e, for t >= 0 and 0 <= s <= 1.
+	denom := cross(dX, dY, eX, eY)
+	tNum := cross(wX, wY, eX, eY)
+	sNum := cross(wX, wY, dX, dY)
+
+	if denom.Sign() == 0 {
+		if sNum.Sign() != 0 {
+			return 0, false // parallel
+		}
+
+		// Collinear: the hit is the nearer end of the segment, unless the
+		// origin lies on it. Positions along the ray are scaled by |d|^2.
+		t1 := dot(wX, wY, dX, dY)
+		t2 := dot(sub(x2, x), sub(y2, y), dX, dY)
+		if t1.Cmp(t2) > 0 {
+			t1, t2 = t2, t1
+		}
+		switch {
+		case t2.Sign() < 0:
+			return 0, false // behind the origin
+		case t1.Sign() <= 0:
+			return 0, true // the origin is on the segment
+		}
+		t, _ := new(big.Rat).SetFrac(t1, dot(dX, dY, dX, dY)).Float64()
+		return t * length, true
+	}
+
+	if denom.Sign() < 0 {
+		denom.Neg(denom)
+		tNum.Neg(tNum)
+		sNum.Neg(sNum)
+	}
+	if tNum.Sign() < 0 || sNum.Sign() < 0 || sNum.Cmp(denom) > 0 {
+		return 0, false
+	}
+
+	t, _ := new(big.Rat).SetFrac(tNum, denom).Float64()
+	return t * length, true
+}
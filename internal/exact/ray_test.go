@@ -0,0 +1,52 @@
+package exact
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRayOverlaps(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		x, y, dx, dy int64
+		box          Box
+		exp          bool
+	}
+
+	b := Box{XMin: 0, XMax: 10, YMin: 0, YMax: 10}
+
+	testCases := map[string]testCase{
+		"starts_inside":          {x: 5, y: 5, dx: -1, dy: 0, box: b, exp: true},
+		"toward":                 {x: -100, y: 5, dx: 1, dy: 0, box: b, exp: true},
+		"away":                   {x: -100, y: 5, dx: -1, dy: 0, box: b},
+		"diagonal_hit":           {x: -5, y: 15, dx: 1, dy: -1, box: b, exp: true},
+		"diagonal_miss":          {x: -11, y: 0, dx: 1, dy: -1, box: b},
+		"along_min_edge":         {x: -5, y: 0, dx: 1, dy: 0, box: b, exp: true},
+		"along_max_edge":         {x: -5, y: 10, dx: 1, dy: 0, box: b},
+		"near_max_corner":        {x: 19, y: 0, dx: -1, dy: 1, box: b, exp: true},
+		"only_max_corner":        {x: 20, y: 20, dx: -1, dy: -1, box: Box{XMin: 0, XMax: 10, YMin: 20, YMax: 30}},
+		"zero_direction_inside":  {x: 5, y: 5, box: b, exp: true},
+		"zero_direction_outside": {x: 15, y: 5, box: b},
+		"empty_box":              {x: -5, y: 0, dx: 1, box: Box{XMin: 0, XMax: 10, YMin: 0, YMax: 0}},
+		"huge_near_miss": {
+			x: math.MinInt64, y: math.MinInt64 + 1, dx: math.MaxInt64, dy: math.MaxInt64,
+			box: Box{XMin: 1 << 62, XMax: 1<<62 + 1, YMin: 1<<62 - 1, YMax: 1 << 62},
+		},
+		"huge_hit": {
+			x: math.MinInt64, y: math.MinInt64 + 1, dx: math.MaxInt64, dy: math.MaxInt64,
+			box: Box{XMin: 1 << 62, XMax: 1<<62 + 1, YMin: 1 << 62, YMax: 1<<62 + 2},
+			exp: true,
+		},
+	}
+
+	for tName, tCase := range testCases {
+		t.Run(tName, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tCase.exp, RayOverlaps(tCase.x, tCase.y, tCase.dx, tCase.dy, tCase.box))
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package objects
+
+import (
+	"image/color"
+	"maps"
+
+	"github.com/chrismarget/two-dimensional-quad-tree/tdqt"
+)
+
+var (
+	_ tdqt.Aggregator = ColorSums{}
+	_ tdqt.Summary    = (*ColorSum)(nil)
+	_ tdqt.Aggregator = ColorHistogram{}
+	_ tdqt.Summary    = (*Histogram)(nil)
+)
+
+// Colored is implemented by the objects in this package which have a single
+// color.
+type Colored interface {
+	Color() color.RGBA
+}
+
+// colorOf returns obj's color, looking through Entity wrappers. It reports
+// false for objects without a single color, such as Composite.
+func colorOf(obj tdqt.Object) (color.RGBA, bool) {
+	for {
+		switch o := obj.(type) {
+		case Colored:
+			return o.Color(), true
+		case interface{ Object() tdqt.Object }:
+			obj = o.Object()
+		default:
+			return color.RGBA{}, false
+		}
+	}
+}
+
+// ColorSums is a tdqt.Aggregator which sums the colors of objects, so that
+// their average color can be found. Objects without a single color (see
+// Colored) are ignored.
+type ColorSums struct{}
+
+func (ColorSums) NewSummary() tdqt.Summary {
+	return &ColorSum{}
+}
+
+// ColorSum is the Summary created by ColorSums.
+type ColorSum struct {
+	Count      uint64
+	R, G, B, A uint64
+}
+
+func (s *ColorSum) Add(obj tdqt.Object) {
+	if c, ok := colorOf(obj); ok {
+		s.Count++
+		s.R, s.G, s.B, s.A = s.R+uint64(c.R), s.G+uint64(c.G), s.B+uint64(c.B), s.A+uint64(c.A)
+	}
+}
+
+func (s *ColorSum) Remove(obj tdqt.Object) {
+	if c, ok := colorOf(obj); ok {
+		s.Count--
+		s.R, s.G, s.B, s.A = s.R-uint64(c.R), s.G-uint64(c.G), s.B-uint64(c.B), s.A-uint64(c.A)
+	}
+}
+
+func (s *ColorSum) Merge(other tdqt.Summary) {
+	o := other.(*ColorSum)
+	s.Count += o.Count
+	s.R, s.G, s.B, s.A = s.R+o.R, s.G+o.G, s.B+o.B, s.A+o.A
+}
+
+// Average returns the mean of the summarized colors, rounded to the nearest
+// value, or the zero color if there are none.
+func (s *ColorSum) Average() color.RGBA {
+	if s.Count == 0 {
+		return color.RGBA{}
+	}
+
+	avg := func(sum uint64) uint8 {
+		return uint8((sum + s.Count/2) / s.Count)
+	}
+
+	return color.RGBA{R: avg(s.R), G: avg(s.G), B: avg(s.B), A: avg(s.A)}
+}
+
+// ColorHistogram is a tdqt.Aggregator which counts the objects of each color.
+// Colors are quantized to the Bits most significant bits of each channel, so
+// that similar colors share a bucket. Bits of 0 (or more than 8) keeps all 8.
+// Objects without a single color (see Colored) are ignored.
+type ColorHistogram struct {
+	Bits uint8
+}
+
+func (h ColorHistogram) NewSummary() tdqt.Summary {
+	var mask uint8 = 0xff
+	if h.Bits > 0 && h.Bits < 8 {
+		mask <<= 8 - h.Bits
+	}
+
+	return &Histogram{mask: mask, counts: make(map[color.RGBA]uint64)}
+}
+
+// Histogram is the Summary created by ColorHistogram.
+type Histogram struct {
+	mask   uint8
+	counts map[color.RGBA]uint64
+}
+
+func (h *Histogram) bucket(c color.RGBA) color.RGBA {
+	return color.RGBA{R: c.R & h.mask, G: c.G & h.mask, B: c.B & h.mask, A: c.A & h.mask}
+}
+
+func (h *Histogram) Add(obj tdqt.Object) {
+	if c, ok := colorOf(obj); ok {
+		h.counts[h.bucket(c)]++
+	}
+}
+
+func (h *Histogram) Remove(obj tdqt.Object) {
+	if c, ok := colorOf(obj); ok {
+		b := h.bucket(c)
+		if h.counts[b]--; h.counts[b] == 0 {
+			delete(h.counts, b)
+		}
+	}
+}
+
+func (h *Histogram) Merge(other tdqt.Summary) {
+	for b, n := range other.(*Histogram).counts {
+		h.counts[b] += n
+	}
+}
+
+// Counts returns the number of objects in each (quantized) color bucket.
+func (h *Histogram) Counts() map[color.RGBA]uint64 {
+	return maps.Clone(h.counts)
+}
+
+// Dominant returns the most common (quantized) color and the number of
+// objects having it. Ties are broken in favor of the lesser color, comparing
+// R, G, B and then A. It returns zero values when there are no objects.
+func (h *Histogram) Dominant() (color.RGBA, uint64) {
+	var best color.RGBA
+	var bestN uint64
+	for b, n := range h.counts {
+		if n > bestN || n == bestN && rgbaLess(b, best) {
+			best, bestN = b, n
+		}
+	}
+
+	return best, bestN
+}
+
+func rgbaLess(a, b color.RGBA) bool {
+	switch {
+	case a.R != b.R:
+		return a.R < b.R
+	case a.G != b.G:
+		return a.G < b.G
+	case a.B != b.B:
+		return a.B < b.B
+	}
+	return a.A < b.A
+}
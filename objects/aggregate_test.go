@@ -0,0 +1,88 @@
+package objects_test
+
+import (
+	"image/color"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/chrismarget/two-dimensional-quad-tree/objects"
+	"github.com/chrismarget/two-dimensional-quad-tree/tdqt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColorSum_Average(t *testing.T) {
+	s := objects.ColorSums{}.NewSummary().(*objects.ColorSum)
+	require.Equal(t, color.RGBA{}, s.Average())
+
+	s.Add(objects.NewColorPoint(0, 0, color.RGBA{R: 10, G: 0, B: 255, A: 255}))
+	s.Add(objects.NewColorPoint(1, 1, color.RGBA{R: 21, G: 1, B: 255, A: 255}))
+	s.Add(objects.NewComposite(objects.NewColorPoint(2, 2, color.RGBA{R: 255})))                    // ignored
+	s.Add(objects.NewEntity(7, objects.NewColorPoint(3, 3, color.RGBA{R: 20, G: 2, B: 0, A: 255}))) // unwrapped
+	require.Equal(t, uint64(3), s.Count)
+	require.Equal(t, color.RGBA{R: 17, G: 1, B: 170, A: 255}, s.Average())
+
+	s.Remove(objects.NewColorPoint(0, 0, color.RGBA{R: 10, G: 0, B: 255, A: 255}))
+	require.Equal(t, color.RGBA{R: 21, G: 2, B: 128, A: 255}, s.Average())
+}
+
+func TestHistogram_Dominant(t *testing.T) {
+	h := objects.ColorHistogram{Bits: 4}.NewSummary().(*objects.Histogram)
+	c, n := h.Dominant()
+	require.Equal(t, color.RGBA{}, c)
+	require.Zero(t, n)
+
+	// 0x12 and 0x1f share a bucket when quantized to 4 bits.
+	h.Add(objects.NewColorPoint(0, 0, color.RGBA{R: 0x12, A: 0xff}))
+	h.Add(objects.NewColorPoint(1, 1, color.RGBA{R: 0x1f, A: 0xff}))
+	h.Add(objects.NewColorPoint(2, 2, color.RGBA{G: 0x80, A: 0xff}))
+	c, n = h.Dominant()
+	require.Equal(t, color.RGBA{R: 0x10, A: 0xf0}, c)
+	require.Equal(t, uint64(2), n)
+
+	// Ties go to the lesser color.
+	h.Remove(objects.NewColorPoint(1, 1, color.RGBA{R: 0x1f, A: 0xff}))
+	c, n = h.Dominant()
+	require.Equal(t, color.RGBA{G: 0x80, A: 0xf0}, c)
+	require.Equal(t, uint64(1), n)
+	require.Len(t, h.Counts(), 2)
+}
+
+func TestTree_Aggregate_Colors(t *testing.T) {
+	everywhere := tdqt.NewRectangle(tdqt.NewLimits(0, 1000), tdqt.NewLimits(0, 1000))
+	palette := []color.RGBA{{R: 255, A: 255}, {G: 255, A: 255}, {B: 255, A: 255}, {R: 128, G: 128, A: 128}}
+	rng := rand.New(rand.NewPCG(17, 18))
+	histogram := objects.ColorHistogram{Bits: 2}
+
+	for _, placement := range []tdqt.PlacementMode{tdqt.PlacementDuplicate, tdqt.PlacementLoose} {
+		t.Run(placement.String(), func(t *testing.T) {
+			tree := tdqt.NewTree(everywhere, tdqt.WithMaxObjects(8), tdqt.WithPlacement(placement),
+				tdqt.WithAggregator(objects.ColorSums{}), tdqt.WithAggregator(histogram))
+
+			for range 1000 {
+				x, y := rng.Int64N(990), rng.Int64N(990)
+				c := palette[rng.IntN(len(palette))]
+				if rng.IntN(4) == 0 {
+					r := tdqt.NewRectangle(tdqt.NewLimits(x, x+1+rng.Int64N(10)), tdqt.NewLimits(y, y+1+rng.Int64N(10)))
+					tree.Insert(objects.NewColorRect(r, c))
+				} else {
+					tree.Insert(objects.NewColorPoint(x, y, c))
+				}
+			}
+
+			for range 20 {
+				x, y := rng.Int64N(900), rng.Int64N(900)
+				area := tdqt.NewRectangle(tdqt.NewLimits(x, x+1+rng.Int64N(1000-x)), tdqt.NewLimits(y, y+1+rng.Int64N(1000-y)))
+
+				sums := objects.ColorSums{}.NewSummary()
+				hist := histogram.NewSummary()
+				for _, obj := range tree.SearchAll(area) {
+					sums.Add(obj)
+					hist.Add(obj)
+				}
+
+				require.Equal(t, sums, tree.Aggregate(area, objects.ColorSums{}))
+				require.Equal(t, hist, tree.Aggregate(area, histogram))
+			}
+		})
+	}
+}
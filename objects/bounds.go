@@ -0,0 +1,25 @@
+package objects
+
+import (
+	"math"
+
+	"github.com/chrismarget/two-dimensional-quad-tree/tdqt"
+)
+
+// boundingBox returns the half-open tdqt.Rectangle covering the inclusive
+// coordinate ranges [xMin, xMax] and [yMin, yMax]. A coordinate of
+// math.MaxInt64 can't fall within any tdqt.Limits, so the exclusive upper
+// bound saturates there rather than overflowing.
+func boundingBox(xMin, xMax, yMin, yMax int64) tdqt.Rectangle {
+	return tdqt.NewRectangle(
+		tdqt.NewLimits(xMin, saturatingIncrement(xMax)),
+		tdqt.NewLimits(yMin, saturatingIncrement(yMax)),
+	)
+}
+
+func saturatingIncrement(i int64) int64 {
+	if i == math.MaxInt64 {
+		return i
+	}
+	return i + 1
+}
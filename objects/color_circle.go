@@ -0,0 +1,280 @@
+package objects
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image/color"
+	"image/draw"
+	"io"
+	"math"
+	"math/big"
+	"slices"
+
+	"github.com/chrismarget/two-dimensional-quad-tree/internal/exact"
+	"github.com/chrismarget/two-dimensional-quad-tree/render"
+	"github.com/chrismarget/two-dimensional-quad-tree/tdqt"
+)
+
+var (
+	_ tdqt.Object         = (*ColorCircle)(nil)
+	_ tdqt.Anchored       = (*ColorCircle)(nil)
+	_ tdqt.Equaler        = (*ColorCircle)(nil)
+	_ tdqt.Bounded        = (*ColorCircle)(nil)
+	_ render.RasterDrawer = (*ColorCircle)(nil)
+	_ render.SVGDrawer    = (*ColorCircle)(nil)
+)
+
+// Containment describes how a shape and a rectangle relate to one another.
+type Containment uint8
+
+const (
+	ContainmentDisjoint        Containment = iota // no points in common
+	ContainmentTouches                            // some points in common, neither contains the other
+	ContainmentRectangleInside                    // the rectangle lies within the shape
+	ContainmentShapeInside                        // the shape lies within the rectangle
+)
+
+func (c Containment) String() string {
+	switch c {
+	case ContainmentDisjoint:
+		return "disjoint"
+	case ContainmentTouches:
+		return "touches"
+	case ContainmentRectangleInside:
+		return "rectangle inside"
+	case ContainmentShapeInside:
+		return "shape inside"
+	}
+	return fmt.Sprintf("Containment(%d)", c)
+}
+
+// ColorCircle is a filled circle, or axis-aligned ellipse, with a color. It
+// includes its boundary: the points (x,y) for which
+//
+//	(x-cx)²/rx² + (y-cy)²/ry² <= 1
+type ColorCircle struct {
+	cx, cy int64
+	rx, ry uint64
+	bounds tdqt.Rectangle
+	color  color.RGBA
+	hash   uint64
+}
+
+func (cc ColorCircle) Anchor() (int64, int64) {
+	return cc.cx, cc.cy
+}
+
+func (cc ColorCircle) Bounds() tdqt.Rectangle {
+	return cc.bounds
+}
+
+func (cc ColorCircle) Hash() uint64 {
+	return cc.hash
+}
+
+func (cc ColorCircle) Color() color.RGBA {
+	return cc.color
+}
+
+func (cc ColorCircle) String() string {
+	if cc.rx == cc.ry {
+		return fmt.Sprintf("(%d,%d) r%d: (%d,%d,%d,%d)", cc.cx, cc.cy, cc.rx, cc.color.R, cc.color.G, cc.color.B, cc.color.A)
+	}
+	return fmt.Sprintf("(%d,%d) r%dx%d: (%d,%d,%d,%d)", cc.cx, cc.cy, cc.rx, cc.ry, cc.color.R, cc.color.G, cc.color.B, cc.color.A)
+}
+
+func (cc ColorCircle) Equal(o tdqt.Object) bool {
+	other, ok := o.(ColorCircle)
+	return ok && cc.cx == other.cx && cc.cy == other.cy && cc.rx == other.rx && cc.ry == other.ry && cc.color == other.color
+}
+
+func (cc *ColorCircle) computeHash() {
+	cc.hash = FnvHash(cc.appendHashInput(nil, tdqt.Object.Hash))
+}
+
+// appendHashInput appends the bytes which identify the ColorCircle to b.
+func (cc ColorCircle) appendHashInput(b []byte, _ tdqt.HashFunc) []byte {
+	bytes := slices.Grow(b, 36)
+	bytes = binary.BigEndian.AppendUint64(bytes, uint64(cc.cx))
+	bytes = binary.BigEndian.AppendUint64(bytes, uint64(cc.cy))
+	bytes = binary.BigEndian.AppendUint64(bytes, cc.rx)
+	bytes = binary.BigEndian.AppendUint64(bytes, cc.ry)
+	bytes = append(bytes, cc.color.R, cc.color.G, cc.color.B, cc.color.A)
+
+	return bytes
+}
+
+func (cc ColorCircle) Overlaps(r tdqt.Rectangle) (bool, bool) {
+	switch cc.Classify(r) {
+	case ContainmentDisjoint:
+		return false, false
+	case ContainmentShapeInside:
+		return true, true
+	default:
+		return true, false
+	}
+}
+
+// Classify describes the relationship between the circle and the rectangle
+// r. The arithmetic is exact for all coordinates and radii.
+func (cc ColorCircle) Classify(r tdqt.Rectangle) Containment {
+	xLimits, yLimits := r.Limits()
+	if xLimits.Min() == xLimits.Max() || yLimits.Min() == yLimits.Max() {
+		return ContainmentDisjoint // nothing overlaps an empty rectangle
+	}
+
+	// The bounding box's max saturates at math.MaxInt64, so compare the
+	// exact extents instead: the shape lies inside r when both extents do.
+	xMin, xMax, _ := axisExtent(cc.cx, cc.rx)
+	yMin, yMax, _ := axisExtent(cc.cy, cc.ry)
+	if xLimits.Min() <= xMin && xMax < xLimits.Max() && yLimits.Min() <= yMin && yMax < yLimits.Max() {
+		return ContainmentShapeInside
+	}
+
+	// Scaling each axis by its radius turns the ellipse into a unit circle
+	// and leaves the rectangle axis-aligned, so the nearest point of the
+	// (closed) rectangle is found by clamping the center to it, one axis at
+	// a time, and the farthest point is one of its corners.
+	nearX, farX := axisDistances(cc.cx, xLimits)
+	nearY, farY := axisDistances(cc.cy, yLimits)
+
+	switch cc.scaledDistance(nearX, nearY).Cmp(cc.scaledRadius()) {
+	case 1:
+		return ContainmentDisjoint
+	case 0:
+		// The ellipse meets the closed rectangle at exactly one point: the
+		// clamped center. It's part of the half-open rectangle unless it's
+		// on the max edge along either axis.
+		if cc.cx >= xLimits.Max() || cc.cy >= yLimits.Max() {
+			return ContainmentDisjoint
+		}
+		return ContainmentTouches
+	}
+
+	if cc.scaledDistance(farX, farY).Cmp(cc.scaledRadius()) <= 0 {
+		return ContainmentRectangleInside
+	}
+
+	return ContainmentTouches
+}
+
+// scaledDistance returns dx²·ry² + dy²·rx², which is the squared distance of
+// the offset (dx,dy) from the center, scaled so that it can be compared with
+// scaledRadius.
+func (cc ColorCircle) scaledDistance(dx, dy uint64) *big.Int {
+	x := new(big.Int).SetUint64(dx)
+	x.Mul(x, new(big.Int).SetUint64(cc.ry))
+	x.Mul(x, x)
+
+	y := new(big.Int).SetUint64(dy)
+	y.Mul(y, new(big.Int).SetUint64(cc.rx))
+	y.Mul(y, y)
+
+	return x.Add(x, y)
+}
+
+// scaledRadius returns rx²·ry², which is the scaledDistance of every point
+// on the boundary.
+func (cc ColorCircle) scaledRadius() *big.Int {
+	result := new(big.Int).SetUint64(cc.rx)
+	result.Mul(result, new(big.Int).SetUint64(cc.ry))
+	return result.Mul(result, result)
+}
+
+// axisDistances returns the distances from c to the nearest and farthest
+// points of the closed interval [l.Min(), l.Max()].
+func axisDistances(c int64, l tdqt.Limits) (uint64, uint64) {
+	_, toMin := exact.Difference(c, l.Min())
+	_, toMax := exact.Difference(c, l.Max())
+
+	switch {
+	case c < l.Min():
+		return toMin, toMax
+	case c > l.Max():
+		return toMax, toMin
+	default:
+		return 0, max(toMin, toMax)
+	}
+}
+
+func (cc ColorCircle) DrawRaster(dst draw.Image, v render.Viewport) {
+	x, y := v.Point(cc.cx, cc.cy)
+	render.FillEllipse(dst, x, y, v.DX(cc.rx), v.DY(cc.ry), cc.color)
+}
+
+func (cc ColorCircle) DrawSVG(w io.Writer, v render.Viewport) error {
+	x, y := v.Point(cc.cx, cc.cy)
+	fill, opacity := render.SVGColor(cc.color)
+	_, err := fmt.Fprintf(w, `<ellipse cx="%g" cy="%g" rx="%g" ry="%g" fill="%s" fill-opacity="%s"/>`+"\n",
+		x, y, v.DX(cc.rx), v.DY(cc.ry), fill, opacity)
+	return err
+}
+
+// NewColorEllipseE returns an axis-aligned ColorCircle centered on (cx,cy)
+// with radius rx along the x-axis and ry along the y-axis. An error is
+// returned if either radius is zero, or if the ellipse extends beyond the
+// int64 coordinate plane.
+func NewColorEllipseE(cx, cy int64, rx, ry uint64, color color.RGBA) (ColorCircle, error) {
+	if rx == 0 || ry == 0 {
+		return ColorCircle{}, errors.New("ellipse radii must be greater than zero")
+	}
+
+	xMin, xMax, ok := axisExtent(cx, rx)
+	if !ok {
+		return ColorCircle{}, fmt.Errorf("ellipse centered at x=%d with radius %d exceeds the coordinate plane", cx, rx)
+	}
+
+	yMin, yMax, ok := axisExtent(cy, ry)
+	if !ok {
+		return ColorCircle{}, fmt.Errorf("ellipse centered at y=%d with radius %d exceeds the coordinate plane", cy, ry)
+	}
+
+	result := ColorCircle{
+		cx:     cx,
+		cy:     cy,
+		rx:     rx,
+		ry:     ry,
+		bounds: boundingBox(xMin, xMax, yMin, yMax),
+		color:  color,
+	}
+
+	result.computeHash()
+
+	return result, nil
+}
+
+// NewColorEllipse is a convenience wrapper around NewColorEllipseE which
+// panics rather than returning an error.
+func NewColorEllipse(cx, cy int64, rx, ry uint64, color color.RGBA) ColorCircle {
+	result, err := NewColorEllipseE(cx, cy, rx, ry, color)
+	if err != nil {
+		panic(err)
+	}
+
+	return result
+}
+
+// NewColorCircleE returns a ColorCircle centered on (cx,cy) with radius r.
+// An error is returned if r is zero, or if the circle extends beyond the
+// int64 coordinate plane.
+func NewColorCircleE(cx, cy int64, r uint64, color color.RGBA) (ColorCircle, error) {
+	return NewColorEllipseE(cx, cy, r, r, color)
+}
+
+// NewColorCircle is a convenience wrapper around NewColorCircleE which panics
+// rather than returning an error.
+func NewColorCircle(cx, cy int64, r uint64, color color.RGBA) ColorCircle {
+	return NewColorEllipse(cx, cy, r, r, color)
+}
+
+// axisExtent returns c-r and c+r, and whether both fit in an int64.
+func axisExtent(c int64, r uint64) (int64, int64, bool) {
+	_, below := exact.Difference(c, math.MinInt64)
+	_, above := exact.Difference(math.MaxInt64, c)
+	if r > below || r > above {
+		return 0, 0, false
+	}
+
+	return int64(uint64(c) - r), int64(uint64(c) + r), true
+}
@@ -0,0 +1,181 @@
+package objects_test
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/chrismarget/two-dimensional-quad-tree/objects"
+	"github.com/chrismarget/two-dimensional-quad-tree/tdqt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColorCircle_Classify(t *testing.T) {
+	circle := objects.NewColorCircle(0, 0, 5, color.RGBA{})       // x²+y² <= 25
+	ellipse := objects.NewColorEllipse(0, 0, 10, 5, color.RGBA{}) // x²/100 + y²/25 <= 1
+
+	type testCase struct {
+		shape                  objects.ColorCircle
+		xMin, xMax, yMin, yMax int64
+		expected               objects.Containment
+	}
+
+	testCases := map[string]testCase{
+		"circle_inside":          {shape: circle, xMin: -5, xMax: 6, yMin: -5, yMax: 6, expected: objects.ContainmentShapeInside},
+		"circle_pokes_out_max":   {shape: circle, xMin: -5, xMax: 5, yMin: -5, yMax: 6, expected: objects.ContainmentTouches},
+		"rect_inside":            {shape: circle, xMin: -3, xMax: 3, yMin: -4, yMax: 4, expected: objects.ContainmentRectangleInside},
+		"rect_corner_on_edge":    {shape: circle, xMin: -3, xMax: 3, yMin: -4, yMax: 5, expected: objects.ContainmentTouches},
+		"rect_overhangs":         {shape: circle, xMin: 2, xMax: 10, yMin: 2, yMax: 10, expected: objects.ContainmentTouches},
+		"rect_near_corner":       {shape: circle, xMin: 4, xMax: 10, yMin: 4, yMax: 10, expected: objects.ContainmentDisjoint},
+		"tangent_at_rect_min":    {shape: circle, xMin: 5, xMax: 10, yMin: -1, yMax: 1, expected: objects.ContainmentTouches},
+		"tangent_at_rect_max":    {shape: circle, xMin: -10, xMax: -5, yMin: -1, yMax: 1, expected: objects.ContainmentDisjoint},
+		"tangent_at_rect_corner": {shape: circle, xMin: 3, xMax: 10, yMin: 4, yMax: 10, expected: objects.ContainmentTouches},
+		"tangent_at_max_corner":  {shape: circle, xMin: -10, xMax: -3, yMin: -10, yMax: -4, expected: objects.ContainmentDisjoint},
+		"rect_far":               {shape: circle, xMin: 50, xMax: 60, yMin: 50, yMax: 60, expected: objects.ContainmentDisjoint},
+		"rect_empty":             {shape: circle, xMin: 0, xMax: 0, yMin: -1, yMax: 1, expected: objects.ContainmentDisjoint},
+		"ellipse_inside":         {shape: ellipse, xMin: -10, xMax: 11, yMin: -5, yMax: 6, expected: objects.ContainmentShapeInside},
+		"ellipse_tangent_x":      {shape: ellipse, xMin: 10, xMax: 20, yMin: -1, yMax: 1, expected: objects.ContainmentTouches},
+		"ellipse_misses_x":       {shape: ellipse, xMin: 11, xMax: 20, yMin: -1, yMax: 1, expected: objects.ContainmentDisjoint},
+		"ellipse_tangent_y":      {shape: ellipse, xMin: -1, xMax: 1, yMin: 5, yMax: 20, expected: objects.ContainmentTouches},
+		"ellipse_misses_y":       {shape: ellipse, xMin: -1, xMax: 1, yMin: 6, yMax: 20, expected: objects.ContainmentDisjoint},
+		"rect_inside_ellipse":    {shape: ellipse, xMin: -8, xMax: 8, yMin: -3, yMax: 3, expected: objects.ContainmentRectangleInside},
+		"rect_outside_ellipse":   {shape: ellipse, xMin: -8, xMax: 8, yMin: -4, yMax: 4, expected: objects.ContainmentTouches},
+	}
+
+	for tName, tCase := range testCases {
+		t.Run(tName, func(t *testing.T) {
+			t.Parallel()
+
+			r := tdqt.NewRectangle(tdqt.NewLimits(tCase.xMin, tCase.xMax), tdqt.NewLimits(tCase.yMin, tCase.yMax))
+			require.Equalf(t, tCase.expected, tCase.shape.Classify(r), "shape %s vs rectangle %s", tCase.shape.String(), r.String())
+
+			overlap, fullyContained := tCase.shape.Overlaps(r)
+			require.Equal(t, tCase.expected != objects.ContainmentDisjoint, overlap)
+			require.Equal(t, tCase.expected == objects.ContainmentShapeInside, fullyContained)
+		})
+	}
+}
+
+func TestColorCircle_HugeCoordinates(t *testing.T) {
+	circle := objects.NewColorCircle(0, 0, math.MaxInt64, color.RGBA{})
+
+	// (math.MaxInt64, 0) is on the circle, but beyond every half-open range.
+	r := tdqt.NewRectangle(tdqt.NewLimits(math.MinInt64, math.MaxInt64), tdqt.NewLimits(math.MinInt64, math.MaxInt64))
+	require.Equal(t, objects.ContainmentTouches, circle.Classify(r))
+
+	edge := objects.NewColorCircle(math.MaxInt64-10, 0, 10, color.RGBA{})
+	r = tdqt.NewRectangle(tdqt.NewLimits(0, math.MaxInt64), tdqt.NewLimits(-100, 100))
+	require.Equal(t, objects.ContainmentTouches, edge.Classify(r))
+	_, fullyContained := edge.Overlaps(r)
+	require.False(t, fullyContained)
+
+	edge = objects.NewColorCircle(math.MaxInt64-11, 0, 10, color.RGBA{})
+	require.Equal(t, objects.ContainmentShapeInside, edge.Classify(r))
+
+	r = tdqt.NewRectangle(tdqt.NewLimits(math.MinInt64, math.MaxInt64-1), tdqt.NewLimits(math.MinInt64, math.MaxInt64))
+	require.Equal(t, objects.ContainmentTouches, circle.Classify(r))
+
+	r = tdqt.NewRectangle(tdqt.NewLimits(math.MaxInt64/2, math.MaxInt64/2+1), tdqt.NewLimits(math.MaxInt64/2, math.MaxInt64/2+1))
+	require.Equal(t, objects.ContainmentRectangleInside, circle.Classify(r))
+
+	r = tdqt.NewRectangle(tdqt.NewLimits(math.MaxInt64-1, math.MaxInt64), tdqt.NewLimits(math.MaxInt64-1, math.MaxInt64))
+	require.Equal(t, objects.ContainmentDisjoint, circle.Classify(r))
+}
+
+func TestNewColorEllipseE(t *testing.T) {
+	_, err := objects.NewColorEllipseE(0, 0, 0, 1, color.RGBA{})
+	require.Error(t, err)
+
+	_, err = objects.NewColorCircleE(math.MaxInt64-1, 0, 2, color.RGBA{})
+	require.Error(t, err)
+
+	_, err = objects.NewColorCircleE(0, math.MinInt64+1, 2, color.RGBA{})
+	require.Error(t, err)
+
+	a := objects.NewColorEllipse(0, 0, 2, 1, color.RGBA{})
+	b := objects.NewColorEllipse(0, 0, 1, 2, color.RGBA{})
+	require.NotEqual(t, a.Hash(), b.Hash())
+	require.Equal(t, objects.NewColorCircle(0, 0, 3, color.RGBA{}).Hash(), objects.NewColorEllipse(0, 0, 3, 3, color.RGBA{}).Hash())
+}
+
+// inEllipse reports whether the sample point (x,y), expressed in units of
+// 1/rasterScale, is within the ellipse.
+func inEllipse(cx, cy, rx, ry, x, y int64) bool {
+	dx, dy := x-cx*rasterScale, y-cy*rasterScale
+	return dx*dx*ry*ry+dy*dy*rx*rx <= rasterScale*rasterScale*rx*rx*ry*ry
+}
+
+func FuzzColorCircle_Classify(f *testing.F) {
+	f.Add(int8(0), int8(0), uint8(5), uint8(5), int8(5), int8(10), int8(-1), int8(1), int64(0), int64(0))
+	f.Add(int8(0), int8(0), uint8(10), uint8(5), int8(-8), int8(8), int8(-3), int8(3), int64(1<<60), int64(-1<<60))
+	f.Add(int8(3), int8(-2), uint8(4), uint8(7), int8(-16), int8(15), int8(-16), int8(15), int64(math.MaxInt64), int64(math.MinInt64))
+
+	f.Fuzz(func(t *testing.T, cx8, cy8 int8, rx8, ry8 uint8, x1, x2, y1, y2 int8, xOffset, yOffset int64) {
+		cx, cy := int64(cx8>>3), int64(cy8>>3)                                         // [-16, 15]
+		rx, ry := 1+int64(rx8%16), 1+int64(ry8%16)                                     // [1, 16]
+		xMin, xMax := min(int64(x1>>2), int64(x2>>2)), max(int64(x1>>2), int64(x2>>2)) // [-32, 31]
+		yMin, yMax := min(int64(y1>>2), int64(y2>>2)), max(int64(y1>>2), int64(y2>>2))
+
+		// Translating everything leaves the answer unchanged, and lets us
+		// exercise coordinates far beyond float64 precision.
+		xOffset = min(max(xOffset, math.MinInt64+64), math.MaxInt64-64)
+		yOffset = min(max(yOffset, math.MinInt64+64), math.MaxInt64-64)
+
+		shape := objects.NewColorEllipse(cx+xOffset, cy+yOffset, uint64(rx), uint64(ry), color.RGBA{})
+		r := tdqt.NewRectangle(tdqt.NewLimits(xMin+xOffset, xMax+xOffset), tdqt.NewLimits(yMin+yOffset, yMax+yOffset))
+		got := shape.Classify(r)
+
+		// The untranslated shape must agree.
+		untranslated := objects.NewColorEllipse(cx, cy, uint64(rx), uint64(ry), color.RGBA{})
+		require.Equal(t, untranslated.Classify(tdqt.NewRectangle(tdqt.NewLimits(xMin, xMax), tdqt.NewLimits(yMin, yMax))), got)
+
+		if xMin == xMax || yMin == yMax {
+			require.Equal(t, objects.ContainmentDisjoint, got)
+			return
+		}
+
+		// sample the half-open rectangle
+		var sampledOverlap, sampledOutside bool
+		for x := xMin * rasterScale; x < xMax*rasterScale; x++ {
+			for y := yMin * rasterScale; y < yMax*rasterScale; y++ {
+				if inEllipse(cx, cy, rx, ry, x, y) {
+					sampledOverlap = true
+				} else {
+					sampledOutside = true
+				}
+			}
+		}
+
+		// The closed rectangle's corners determine whether it's within the
+		// (convex) ellipse.
+		rectInside := true
+		for _, x := range []int64{xMin, xMax} {
+			for _, y := range []int64{yMin, yMax} {
+				rectInside = rectInside && inEllipse(cx, cy, rx, ry, x*rasterScale, y*rasterScale)
+			}
+		}
+
+		shapeInside := cx-rx >= xMin && cx+rx < xMax && cy-ry >= yMin && cy+ry < yMax
+
+		switch got {
+		case objects.ContainmentDisjoint:
+			require.False(t, sampledOverlap, "shape %s vs rectangle %s: sample overlap, but classified disjoint", untranslated, r)
+		case objects.ContainmentShapeInside:
+			require.True(t, shapeInside)
+		case objects.ContainmentRectangleInside:
+			require.True(t, rectInside)
+			require.False(t, sampledOutside)
+		case objects.ContainmentTouches:
+			require.False(t, shapeInside)
+			require.False(t, rectInside)
+			// The nearest point of the rectangle is a lattice point, so a
+			// touching ellipse must contain some sample point of the
+			// closed rectangle.
+			nx, ny := min(max(cx, xMin), xMax), min(max(cy, yMin), yMax)
+			require.True(t, inEllipse(cx, cy, rx, ry, nx*rasterScale, ny*rasterScale))
+		}
+		if sampledOverlap {
+			require.NotEqual(t, objects.ContainmentDisjoint, got)
+		}
+	})
+}
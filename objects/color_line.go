@@ -4,14 +4,26 @@ import (
 	"encoding/binary"
 	"fmt"
 	"image/color"
-	"log"
+	"image/draw"
+	"io"
+	"slices"
 
+	"github.com/chrismarget/two-dimensional-quad-tree/internal/exact"
+	"github.com/chrismarget/two-dimensional-quad-tree/render"
 	"github.com/chrismarget/two-dimensional-quad-tree/tdqt"
-	"github.com/twpayne/go-geom"
-	"github.com/twpayne/go-geom/xy/lineintersector"
 )
 
-var _ tdqt.Object = (*ColorLine)(nil)
+var (
+	_ tdqt.Object             = (*ColorLine)(nil)
+	_ tdqt.Anchored           = (*ColorLine)(nil)
+	_ tdqt.Equaler            = (*ColorLine)(nil)
+	_ tdqt.Bounded            = (*ColorLine)(nil)
+	_ tdqt.RayIntersector     = (*ColorLine)(nil)
+	_ tdqt.SegmentIntersector = (*ColorLine)(nil)
+
+	_ render.RasterDrawer = (*ColorLine)(nil)
+	_ render.SVGDrawer    = (*ColorLine)(nil)
+)
 
 type ColorLine struct {
 	x1    int64
@@ -26,85 +38,102 @@ func (cl ColorLine) Hash() uint64 {
 	return cl.hash
 }
 
+// Anchor returns the midpoint of the line.
+func (cl ColorLine) Anchor() (int64, int64) {
+	return average(cl.x1, cl.x2), average(cl.y1, cl.y2)
+}
+
+func (cl ColorLine) Bounds() tdqt.Rectangle {
+	return boundingBox(min(cl.x1, cl.x2), max(cl.x1, cl.x2), min(cl.y1, cl.y2), max(cl.y1, cl.y2))
+}
+
+func (cl ColorLine) Color() color.RGBA {
+	return cl.color
+}
+
 func (cl ColorLine) String() string {
 	return fmt.Sprintf("(%d,%d)<->(%d,%d): (%d,%d,%d,%d)", cl.x1, cl.y1, cl.x2, cl.y2, cl.color.R, cl.color.G, cl.color.B, cl.color.A)
 }
 
+func (cl ColorLine) Equal(o tdqt.Object) bool {
+	other, ok := o.(ColorLine)
+	return ok && cl.x1 == other.x1 && cl.y1 == other.y1 && cl.x2 == other.x2 && cl.y2 == other.y2 && cl.color == other.color
+}
+
 func (cl *ColorLine) computeHash() {
-	bytes := make([]byte, 0, 36)
+	cl.hash = FnvHash(cl.appendHashInput(nil, tdqt.Object.Hash))
+}
+
+// appendHashInput appends the bytes which identify the ColorLine to b.
+func (cl ColorLine) appendHashInput(b []byte, _ tdqt.HashFunc) []byte {
+	bytes := slices.Grow(b, 36)
 	bytes = binary.BigEndian.AppendUint64(bytes, uint64(cl.x1))
 	bytes = binary.BigEndian.AppendUint64(bytes, uint64(cl.y1))
 	bytes = binary.BigEndian.AppendUint64(bytes, uint64(cl.x2))
 	bytes = binary.BigEndian.AppendUint64(bytes, uint64(cl.y2))
 	bytes = append(bytes, cl.color.R, cl.color.G, cl.color.B, cl.color.A)
 
-	cl.hash = FnvHash(bytes)
+	return bytes
 }
 
 func (cl ColorLine) Overlaps(r tdqt.Rectangle) (bool, bool) {
-	oi1 := octothorpeInfo(r, cl.x1, cl.y1)
-	oi2 := octothorpeInfo(r, cl.x2, cl.y2)
-	if oi1 == (row2&col2) || oi2 == (row2&col2) {
-		// at least one point is in the rectangle
-		return true, oi1&oi2 == row2&col2
-	}
+	return lineOverlaps(cl.x1, cl.y1, cl.x2, cl.y2, r)
+}
 
-	bothOiAnded := oi1 & oi2
-	if bothOiAnded == (row2 | col2) {
-		return true, true // both points are in the rectangle
-	}
+// lineOverlaps indicates whether the line (x1,y1)<->(x2,y2) overlaps, and is
+// fully contained by, the rectangle r.
+func lineOverlaps(x1, y1, x2, y2 int64, r tdqt.Rectangle) (bool, bool) {
+	return exact.SegmentOverlaps(x1, y1, x2, y2, box(r))
+}
 
-	switch bothOiAnded & rowBits {
-	case row1: // both points in top row
-		return false, false // too high
-	case row3: // both points in bottom row
-		return false, false // too low
-	case row2: // both points in center row
-		if (oi1|oi2)&colBits == col1|col3 {
-			return true, false // line crosses left/right through rectangle
-		}
+// box returns the exact.Box covering the same points as r.
+func box(r tdqt.Rectangle) exact.Box {
+	xLimits, yLimits := r.Limits()
+	return exact.Box{
+		XMin: xLimits.Min(), XMax: xLimits.Max(),
+		YMin: yLimits.Min(), YMax: yLimits.Max(),
 	}
+}
 
-	switch bothOiAnded & colBits {
-	case col1:
-		return false, false // both points in col1 (too left)
-	case col3:
-		return false, false // both points in col3 (too right)
-	case col2:
-		if (oi1|oi2)&rowBits == row1|row3 {
-			return true, false // line crosses top/bottom through rectangle
-		}
-	}
+// Intersects reports whether the ColorLine shares any point with o, including
+// where they merely touch. The test is exact.
+func (cl ColorLine) Intersects(o ColorLine) bool {
+	return exact.SegmentsIntersect(cl.x1, cl.y1, cl.x2, cl.y2, o.x1, o.y1, o.x2, o.y2)
+}
 
-	// If we got here, the line must have:
-	//  - One endpoint in the center row and one in the max/min column
-	//  - One endpoint in the center column and one in the max/min row
-	// No shortcuts available. See if the line intersects the rectangle by
-	// checking for intersections with each side of the rectangle.
+func (cl ColorLine) IntersectsSegment(x1, y1, x2, y2 int64) bool {
+	return exact.SegmentsIntersect(cl.x1, cl.y1, cl.x2, cl.y2, x1, y1, x2, y2)
+}
 
-	xLimits, yLimits := r.Limits()
+func (cl ColorLine) IntersectRay(x, y, dx, dy int64) (float64, bool) {
+	return exact.RaySegmentDistance(x, y, dx, dy, cl.x1, cl.y1, cl.x2, cl.y2)
+}
 
-	lineEPs := [2]geom.Coord{
-		{float64(cl.x1), float64(cl.y1)}, {float64(cl.x2), float64(cl.y2)},
+// LinesIntersect reports whether a and b are both ColorLines which intersect
+// one another. It's suitable for use with tdqt.Tree.Collisions.
+func LinesIntersect(a, b tdqt.Object) bool {
+	la, ok := a.(ColorLine)
+	if !ok {
+		return false
 	}
+	lb, ok := b.(ColorLine)
 
-	for _, sideEPs := range [4][2]geom.Coord{ // all 4 sides of the rectangle
-		{{float64(xLimits.Min()), float64(yLimits.Min())}, {float64(xLimits.Max()), float64(yLimits.Min())}},
-		{{float64(xLimits.Max()), float64(yLimits.Min())}, {float64(xLimits.Max()), float64(yLimits.Max())}},
-		{{float64(xLimits.Max()), float64(yLimits.Max())}, {float64(xLimits.Min()), float64(yLimits.Max())}},
-		{{float64(xLimits.Min()), float64(yLimits.Max())}, {float64(xLimits.Min()), float64(yLimits.Min())}},
-	} {
-		li := lineintersector.LineIntersectsLine(
-			lineintersector.NonRobustLineIntersector{},
-			lineEPs[0], lineEPs[1], sideEPs[0], sideEPs[1],
-		)
-		if li.HasIntersection() {
-			// possible false positive here when an endpoint lands on a maximum boundary, but we'll live with it
-			return true, false
-		}
-	}
+	return ok && la.Intersects(lb)
+}
+
+func (cl ColorLine) DrawRaster(dst draw.Image, v render.Viewport) {
+	x1, y1 := v.Point(cl.x1, cl.y1)
+	x2, y2 := v.Point(cl.x2, cl.y2)
+	render.Line(dst, x1, y1, x2, y2, cl.color)
+}
 
-	return false, false
+func (cl ColorLine) DrawSVG(w io.Writer, v render.Viewport) error {
+	x1, y1 := v.Point(cl.x1, cl.y1)
+	x2, y2 := v.Point(cl.x2, cl.y2)
+	stroke, opacity := render.SVGColor(cl.color)
+	_, err := fmt.Fprintf(w, `<line x1="%g" y1="%g" x2="%g" y2="%g" stroke="%s" stroke-opacity="%s"/>`+"\n",
+		x1, y1, x2, y2, stroke, opacity)
+	return err
 }
 
 func NewColorLine(x1, y1, x2, y2 int64, color color.RGBA) ColorLine {
@@ -121,79 +150,7 @@ func NewColorLine(x1, y1, x2, y2 int64, color color.RGBA) ColorLine {
 	return result
 }
 
-const (
-	col1    = 1 << 0
-	col2    = 1 << 1
-	col3    = 1 << 2
-	colBits = col1 | col2 | col3
-
-	row1    = 1 << 3
-	row2    = 1 << 4
-	row3    = 1 << 5
-	rowBits = row1 | row2 | row3
-)
-
-// octothorpeInfo takes an (x,y) coordinate pair returns a byte with exactly two
-// bits set. The lower bit indicates the coordinate pair's relationship with the
-// rectangle's x-axis limits. The higher bit indicates the coordinate pair's
-// relationship with the rectangle's y-axis limits.
-//
-// One of these nine values will be returned: [9, 10, 12, 17, 18, 20, 33, 34, 36]
-//
-//	  col1                 col2                 col3
-//	00000001    x-min    00000010    x-max    00000100
-//	              |                    |
-//	00001001      |      00001010      |      00001100       00001000
-//	    9         |         10         |         12            row1
-//	              |                    |
-//
-// --------------------+--------------------+-------------------- y-max
-//
-//	              |                    |
-//	00010001      |      00010010      |      00010100       00010000
-//	   17         |         18         |         20            row2
-//	              |                    |
-//
-// --------------------+--------------------+-------------------- y-min
-//
-//	              |                    |
-//	00100001      |      00100010      |      00100100       00100000
-//	   33         |         34         |         36            row3
-//	              |                    |
-func octothorpeInfo(r tdqt.Rectangle, x, y int64) byte {
-	xRange, yRange := r.Limits()
-	xMin := xRange.Min()
-	xMax := xRange.Max()
-	yMin := yRange.Min()
-	yMax := yRange.Max()
-
-	var result byte
-	switch {
-	case x >= xMax:
-		result = col3
-	case x >= xMin:
-		result = col2
-	case x < xMin:
-		result = col1
-	}
-
-	switch {
-	case y >= yMax:
-		return result | row1
-	case y >= yMin:
-		return result | row2
-	case y < yMin:
-		return result | row3
-	}
-
-	if result&(col1|col2|col3) == 0 {
-		log.Panic("impossible situation in octothorpeInfo x-axis")
-	}
-
-	if result&(row1|row2|row3) == 0 {
-		log.Panic("impossible situation in octothorpeInfo y-axis")
-	}
-
-	log.Panic("impossible situation in octothorpeInfo")
-	return 0
+// average returns the mean of a and b (rounded down) without risk of overflow.
+func average(a, b int64) int64 {
+	return (a & b) + ((a ^ b) >> 1)
 }
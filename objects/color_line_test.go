@@ -2,6 +2,8 @@ package objects_test
 
 import (
 	"image/color"
+	"math"
+	"slices"
 	"strconv"
 	"testing"
 
@@ -317,28 +319,25 @@ func TestColorLine_Overlaps(t *testing.T) {
 
 		// diagonal single point intersection test cases
 		"o4_o2_corner": {
-			x1:      5,
-			y1:      15,
-			x2:      15,
-			y2:      25,
-			r:       testRectangle,
-			overlap: true, // top left corner intersection, prefer this not match, but it does
+			x1: 5,
+			y1: 15,
+			x2: 15,
+			y2: 25,
+			r:  testRectangle, // top left corner intersection is outside the half-open rectangle
 		},
 		"o2_o6_corner": {
-			x1:      15,
-			y1:      25,
-			x2:      25,
-			y2:      15,
-			r:       testRectangle,
-			overlap: true, // top right corner intersection, prefer this not match, but it does
+			x1: 15,
+			y1: 25,
+			x2: 25,
+			y2: 15,
+			r:  testRectangle, // top right corner intersection is outside the half-open rectangle
 		},
 		"o6_o8_corner": {
-			x1:      25,
-			y1:      15,
-			x2:      15,
-			y2:      5,
-			r:       testRectangle,
-			overlap: true, // bottom right corner intersection, prefer this not match, but it does
+			x1: 25,
+			y1: 15,
+			x2: 15,
+			y2: 5,
+			r:  testRectangle, // bottom right corner intersection is outside the half-open rectangle
 		},
 		"o8_o4_corner": {
 			x1:      15,
@@ -402,3 +401,198 @@ func TestColorLine_Overlaps(t *testing.T) {
 		})
 	}
 }
+
+// TestColorLine_Overlaps_EndpointInside pairs an endpoint inside the
+// rectangle with endpoints in each of the surrounding regions, including
+// points on the rectangle's (exclusive) max edges and far-flung points.
+func TestColorLine_Overlaps_EndpointInside(t *testing.T) {
+	r := tdqt.NewRectangle(tdqt.NewLimits(10, 20), tdqt.NewLimits(10, 20))
+
+	inside := [][2]int64{{10, 10}, {19, 19}, {15, 12}, {10, 19}}
+	others := [][2]int64{
+		{5, 25}, {15, 25}, {25, 25}, // above
+		{5, 15}, {25, 15}, // beside
+		{5, 5}, {15, 5}, {25, 5}, // below
+		{20, 15}, {15, 20}, {20, 20}, // on the max edges
+		{math.MinInt64, math.MaxInt64}, {math.MaxInt64, math.MinInt64}, {math.MaxInt64, math.MaxInt64},
+	}
+
+	for _, in := range inside {
+		for _, other := range append(others, inside...) {
+			isInside := slices.Contains(inside, other)
+			for _, line := range []objects.ColorLine{
+				objects.NewColorLine(in[0], in[1], other[0], other[1], color.RGBA{}),
+				objects.NewColorLine(other[0], other[1], in[0], in[1], color.RGBA{}),
+			} {
+				overlap, contained := line.Overlaps(r)
+				require.Truef(t, overlap, "line %s should overlap rectangle %s", line, r)
+				require.Equalf(t, isInside, contained, "line %s contained by rectangle %s", line, r)
+			}
+		}
+	}
+}
+
+// bruteForceLineOverlaps checks a line against a rectangle by walking along
+// it. Every boundary crossing happens at a multiple of 1/(|dx|*|dy|) of the
+// line's length, so checking each multiple of half that step finds any
+// overlap, even one which is only a single point.
+func bruteForceLineOverlaps(x1, y1, x2, y2, xMin, xMax, yMin, yMax int64) (bool, bool) {
+	dx, dy := x2-x1, y2-y1
+	steps := 2 * max(1, abs(dx)) * max(1, abs(dy))
+
+	inside := func(k int64) bool {
+		x := x1*steps + k*dx // x coordinate, scaled up by steps
+		y := y1*steps + k*dy // y coordinate, scaled up by steps
+		return xMin*steps <= x && x < xMax*steps && yMin*steps <= y && y < yMax*steps
+	}
+
+	var overlap bool
+	for k := range steps + 1 {
+		if inside(k) {
+			overlap = true
+			break
+		}
+	}
+
+	return overlap, inside(0) && inside(steps)
+}
+
+func abs(i int64) int64 {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
+
+func FuzzColorLine_Overlaps(f *testing.F) {
+	f.Add(int8(5), int8(15), int8(15), int8(25), int8(10), int8(20), int8(10), int8(20), int64(0), int64(0))
+	f.Add(int8(25), int8(15), int8(15), int8(5), int8(10), int8(20), int8(10), int8(20), int64(0), int64(0))
+	f.Add(int8(15), int8(5), int8(5), int8(15), int8(10), int8(20), int8(10), int8(20), int64(0), int64(0))
+	f.Add(int8(6), int8(14), int8(16), int8(24), int8(10), int8(20), int8(10), int8(20), int64(1<<53), int64(1<<53))
+	f.Add(int8(-100), int8(-1), int8(100), int8(1), int8(-1), int8(0), int8(0), int8(1), int64(math.MaxInt64), int64(math.MinInt64))
+	f.Add(int8(-3), int8(7), int8(9), int8(-2), int8(0), int8(5), int8(0), int8(3), int64(1<<62+1), int64(-1<<62-3))
+
+	f.Fuzz(func(t *testing.T, x1, y1, x2, y2, xMin, xMax, yMin, yMax int8, xOffset, yOffset int64) {
+		if xMin > xMax {
+			xMin, xMax = xMax, xMin
+		}
+		if yMin > yMax {
+			yMin, yMax = yMax, yMin
+		}
+
+		// Translating everything leaves the answer unchanged, and lets us
+		// exercise coordinates far beyond float64 precision.
+		xOffset = min(max(xOffset, math.MinInt64+128), math.MaxInt64-128)
+		yOffset = min(max(yOffset, math.MinInt64+128), math.MaxInt64-128)
+
+		line := objects.NewColorLine(
+			int64(x1)+xOffset, int64(y1)+yOffset,
+			int64(x2)+xOffset, int64(y2)+yOffset,
+			color.RGBA{},
+		)
+		r := tdqt.NewRectangle(
+			tdqt.NewLimits(int64(xMin)+xOffset, int64(xMax)+xOffset),
+			tdqt.NewLimits(int64(yMin)+yOffset, int64(yMax)+yOffset),
+		)
+
+		expOverlap, expContained := bruteForceLineOverlaps(
+			int64(x1), int64(y1), int64(x2), int64(y2),
+			int64(xMin), int64(xMax), int64(yMin), int64(yMax),
+		)
+
+		overlap, contained := line.Overlaps(r)
+		require.Equalf(t, expOverlap, overlap, "line %s overlap rectangle %s", line, r)
+		require.Equalf(t, expContained, contained, "line %s contained by rectangle %s", line, r)
+	})
+}
+
+func TestColorLine_Intersects(t *testing.T) {
+	type testCase struct {
+		a, b      [4]int64
+		intersect bool
+	}
+
+	testCases := map[string]testCase{
+		"cross":                {a: [4]int64{0, 0, 10, 10}, b: [4]int64{0, 10, 10, 0}, intersect: true},
+		"parallel":             {a: [4]int64{0, 0, 10, 0}, b: [4]int64{0, 1, 10, 1}},
+		"apart":                {a: [4]int64{0, 0, 10, 10}, b: [4]int64{20, 0, 30, 10}},
+		"would_cross_extended": {a: [4]int64{0, 0, 4, 4}, b: [4]int64{0, 10, 10, 0}},
+		"endpoints_touch":      {a: [4]int64{0, 0, 5, 5}, b: [4]int64{5, 5, 10, 0}, intersect: true},
+		"t_junction":           {a: [4]int64{0, 0, 10, 0}, b: [4]int64{5, 0, 5, 10}, intersect: true},
+		"collinear_overlap":    {a: [4]int64{0, 0, 10, 10}, b: [4]int64{5, 5, 15, 15}, intersect: true},
+		"collinear_apart":      {a: [4]int64{0, 0, 4, 4}, b: [4]int64{5, 5, 15, 15}},
+		"point_on_line":        {a: [4]int64{0, 0, 10, 10}, b: [4]int64{3, 3, 3, 3}, intersect: true},
+		"point_off_line":       {a: [4]int64{0, 0, 10, 10}, b: [4]int64{3, 4, 3, 4}},
+		"near_miss_huge": {
+			a: [4]int64{math.MinInt64, math.MinInt64, math.MaxInt64, math.MaxInt64 - 1},
+			b: [4]int64{math.MaxInt64 - 1, math.MaxInt64, math.MaxInt64, math.MaxInt64},
+		},
+		"cross_huge": {
+			a:         [4]int64{math.MinInt64, math.MinInt64, math.MaxInt64, math.MaxInt64},
+			b:         [4]int64{math.MinInt64, math.MaxInt64, math.MaxInt64, math.MinInt64},
+			intersect: true,
+		},
+	}
+
+	for tName, tCase := range testCases {
+		t.Run(tName, func(t *testing.T) {
+			a := objects.NewColorLine(tCase.a[0], tCase.a[1], tCase.a[2], tCase.a[3], color.RGBA{})
+			b := objects.NewColorLine(tCase.b[0], tCase.b[1], tCase.b[2], tCase.b[3], color.RGBA{})
+			require.Equal(t, tCase.intersect, a.Intersects(b))
+			require.Equal(t, tCase.intersect, b.Intersects(a))
+			require.Equal(t, tCase.intersect, objects.LinesIntersect(a, b))
+		})
+	}
+
+	require.False(t, objects.LinesIntersect(objects.NewColorLine(0, 0, 1, 1, color.RGBA{}), objects.NewColorPoint(0, 0, color.RGBA{})))
+}
+
+func TestColorLine_IntersectRay(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		line [4]int64
+		ray  [4]int64 // x, y, dx, dy
+		hit  bool
+		dist float64
+	}
+
+	testCases := map[string]testCase{
+		"head_on":          {line: [4]int64{10, -5, 10, 5}, ray: [4]int64{0, 0, 1, 0}, hit: true, dist: 10},
+		"long_direction":   {line: [4]int64{10, -5, 10, 5}, ray: [4]int64{0, 0, 1000, 0}, hit: true, dist: 10},
+		"diagonal":         {line: [4]int64{0, 10, 10, 0}, ray: [4]int64{0, 0, 1, 1}, hit: true, dist: 5 * math.Sqrt2},
+		"behind":           {line: [4]int64{-10, -5, -10, 5}, ray: [4]int64{0, 0, 1, 0}},
+		"passes_end":       {line: [4]int64{10, 1, 10, 5}, ray: [4]int64{0, 0, 1, 0}},
+		"grazes_end":       {line: [4]int64{10, 0, 10, 5}, ray: [4]int64{0, 0, 1, 0}, hit: true, dist: 10},
+		"parallel":         {line: [4]int64{0, 1, 10, 1}, ray: [4]int64{0, 0, 1, 0}},
+		"collinear_ahead":  {line: [4]int64{20, 0, 5, 0}, ray: [4]int64{0, 0, 3, 0}, hit: true, dist: 5},
+		"collinear_behind": {line: [4]int64{-20, 0, -5, 0}, ray: [4]int64{0, 0, 3, 0}},
+		"collinear_on":     {line: [4]int64{-20, 0, 5, 0}, ray: [4]int64{0, 0, 3, 0}, hit: true, dist: 0},
+		"starts_on":        {line: [4]int64{0, -5, 0, 5}, ray: [4]int64{0, 0, 1, 1}, hit: true, dist: 0},
+		"point_ahead":      {line: [4]int64{6, 8, 6, 8}, ray: [4]int64{0, 0, 3, 4}, hit: true, dist: 10},
+		"point_off":        {line: [4]int64{6, 9, 6, 9}, ray: [4]int64{0, 0, 3, 4}},
+		"huge_near_miss": {
+			line: [4]int64{math.MaxInt64 - 1, math.MinInt64, math.MaxInt64 - 1, math.MaxInt64 - 1},
+			ray:  [4]int64{math.MinInt64, math.MinInt64 + 1, 1, 1},
+		},
+		"huge_hit": {
+			line: [4]int64{math.MaxInt64 - 1, math.MinInt64, math.MaxInt64 - 1, math.MaxInt64},
+			ray:  [4]int64{math.MinInt64, math.MinInt64 + 1, 1, 1},
+			hit:  true,
+			dist: math.Sqrt2 * (math.MaxInt64 - 1 - math.MinInt64),
+		},
+	}
+
+	for tName, tCase := range testCases {
+		t.Run(tName, func(t *testing.T) {
+			t.Parallel()
+
+			l := objects.NewColorLine(tCase.line[0], tCase.line[1], tCase.line[2], tCase.line[3], color.RGBA{})
+			dist, hit := l.IntersectRay(tCase.ray[0], tCase.ray[1], tCase.ray[2], tCase.ray[3])
+			require.Equal(t, tCase.hit, hit)
+			if hit {
+				require.InDelta(t, tCase.dist, dist, 1e-9)
+			}
+		})
+	}
+}
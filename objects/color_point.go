@@ -4,11 +4,25 @@ import (
 	"encoding/binary"
 	"fmt"
 	"image/color"
+	"image/draw"
+	"io"
+	"slices"
 
+	"github.com/chrismarget/two-dimensional-quad-tree/internal/exact"
+	"github.com/chrismarget/two-dimensional-quad-tree/render"
 	"github.com/chrismarget/two-dimensional-quad-tree/tdqt"
 )
 
-var _ tdqt.Object = (*ColorPoint)(nil)
+var (
+	_ tdqt.Object             = (*ColorPoint)(nil)
+	_ tdqt.Anchored           = (*ColorPoint)(nil)
+	_ tdqt.Equaler            = (*ColorPoint)(nil)
+	_ tdqt.Bounded            = (*ColorPoint)(nil)
+	_ tdqt.SegmentIntersector = (*ColorPoint)(nil)
+
+	_ render.RasterDrawer = (*ColorPoint)(nil)
+	_ render.SVGDrawer    = (*ColorPoint)(nil)
+)
 
 type ColorPoint struct {
 	x     int64
@@ -21,17 +35,39 @@ func (cp ColorPoint) Hash() uint64 {
 	return cp.hash
 }
 
+func (cp ColorPoint) Anchor() (int64, int64) {
+	return cp.x, cp.y
+}
+
+func (cp ColorPoint) Bounds() tdqt.Rectangle {
+	return boundingBox(cp.x, cp.x, cp.y, cp.y)
+}
+
+func (cp ColorPoint) Color() color.RGBA {
+	return cp.color
+}
+
 func (cp ColorPoint) String() string {
 	return fmt.Sprintf("(%d,%d): (%d,%d,%d,%d)", cp.x, cp.y, cp.color.R, cp.color.G, cp.color.B, cp.color.A)
 }
 
+func (cp ColorPoint) Equal(o tdqt.Object) bool {
+	other, ok := o.(ColorPoint)
+	return ok && cp.x == other.x && cp.y == other.y && cp.color == other.color
+}
+
 func (cp *ColorPoint) computeHash() {
-	bytes := make([]byte, 0, 20)
+	cp.hash = FnvHash(cp.appendHashInput(nil, tdqt.Object.Hash))
+}
+
+// appendHashInput appends the bytes which identify the ColorPoint to b.
+func (cp ColorPoint) appendHashInput(b []byte, _ tdqt.HashFunc) []byte {
+	bytes := slices.Grow(b, 20)
 	bytes = binary.BigEndian.AppendUint64(bytes, uint64(cp.x))
 	bytes = binary.BigEndian.AppendUint64(bytes, uint64(cp.y))
 	bytes = append(bytes, cp.color.R, cp.color.G, cp.color.B, cp.color.A)
 
-	cp.hash = FnvHash(bytes)
+	return bytes
 }
 
 func (cp ColorPoint) Overlaps(r tdqt.Rectangle) (bool, bool) {
@@ -44,6 +80,23 @@ func (cp ColorPoint) Overlaps(r tdqt.Rectangle) (bool, bool) {
 	return false, false
 }
 
+func (cp ColorPoint) IntersectsSegment(x1, y1, x2, y2 int64) bool {
+	return exact.SegmentsIntersect(x1, y1, x2, y2, cp.x, cp.y, cp.x, cp.y)
+}
+
+func (cp ColorPoint) DrawRaster(dst draw.Image, v render.Viewport) {
+	x, y := v.Point(cp.x, cp.y)
+	render.FillRect(dst, x, y, x, y, cp.color)
+}
+
+func (cp ColorPoint) DrawSVG(w io.Writer, v render.Viewport) error {
+	x, y := v.Point(cp.x, cp.y)
+	fill, opacity := render.SVGColor(cp.color)
+	_, err := fmt.Fprintf(w, `<rect x="%g" y="%g" width="1" height="1" fill="%s" fill-opacity="%s"/>`+"\n",
+		x, y, fill, opacity)
+	return err
+}
+
 func NewColorPoint(x, y int64, color color.RGBA) ColorPoint {
 	result := ColorPoint{
 		x:     x,
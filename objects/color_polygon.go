@@ -0,0 +1,254 @@
+package objects
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image/color"
+	"image/draw"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/chrismarget/two-dimensional-quad-tree/internal/exact"
+	"github.com/chrismarget/two-dimensional-quad-tree/render"
+	"github.com/chrismarget/two-dimensional-quad-tree/tdqt"
+)
+
+var (
+	_ tdqt.Object             = (*ColorPolygon)(nil)
+	_ tdqt.Anchored           = (*ColorPolygon)(nil)
+	_ tdqt.Equaler            = (*ColorPolygon)(nil)
+	_ tdqt.Bounded            = (*ColorPolygon)(nil)
+	_ tdqt.SegmentIntersector = (*ColorPolygon)(nil)
+	_ render.RasterDrawer     = (*ColorPolygon)(nil)
+	_ render.SVGDrawer        = (*ColorPolygon)(nil)
+)
+
+// ColorPolygon is a filled polygon with a color. It is described by an outer
+// ring and zero or more holes. Rings are implicitly closed: the last vertex
+// connects back to the first. The polygon includes its boundary (including
+// the edges of its holes), but not the interior of its holes. Holes must lie
+// within the outer ring and must not overlap one another. Self-intersecting
+// rings are filled according to the even-odd rule.
+type ColorPolygon struct {
+	rings  [][]Vertex // rings[0] is the outer ring, the rest are holes
+	bounds tdqt.Rectangle
+	color  color.RGBA
+	hash   uint64
+}
+
+func (cp ColorPolygon) Anchor() (int64, int64) {
+	return cp.bounds.Center()
+}
+
+func (cp ColorPolygon) Bounds() tdqt.Rectangle {
+	return cp.bounds
+}
+
+func (cp ColorPolygon) Hash() uint64 {
+	return cp.hash
+}
+
+func (cp ColorPolygon) Color() color.RGBA {
+	return cp.color
+}
+
+func (cp ColorPolygon) String() string {
+	var sb strings.Builder
+	for i, ring := range cp.rings {
+		if i > 0 {
+			sb.WriteString(" - ")
+		}
+		sb.WriteString("[")
+		for j, v := range ring {
+			if j > 0 {
+				sb.WriteString(",")
+			}
+			sb.WriteString(v.String())
+		}
+		sb.WriteString("]")
+	}
+
+	return fmt.Sprintf("%s: (%d,%d,%d,%d)", sb.String(), cp.color.R, cp.color.G, cp.color.B, cp.color.A)
+}
+
+func (cp ColorPolygon) Equal(o tdqt.Object) bool {
+	other, ok := o.(ColorPolygon)
+	return ok && cp.color == other.color && slices.EqualFunc(cp.rings, other.rings, slices.Equal)
+}
+
+func (cp *ColorPolygon) computeHash() {
+	cp.hash = FnvHash(cp.appendHashInput(nil, tdqt.Object.Hash))
+}
+
+// appendHashInput appends the bytes which identify the ColorPolygon to b.
+func (cp ColorPolygon) appendHashInput(b []byte, _ tdqt.HashFunc) []byte {
+	bytes := slices.Grow(b, 4)
+	for _, ring := range cp.rings {
+		// ring lengths keep differently-divided vertex lists distinct
+		bytes = binary.BigEndian.AppendUint64(bytes, uint64(len(ring)))
+		for _, v := range ring {
+			bytes = binary.BigEndian.AppendUint64(bytes, uint64(v.X))
+			bytes = binary.BigEndian.AppendUint64(bytes, uint64(v.Y))
+		}
+	}
+	bytes = append(bytes, cp.color.R, cp.color.G, cp.color.B, cp.color.A)
+
+	return bytes
+}
+
+func (cp ColorPolygon) Overlaps(r tdqt.Rectangle) (bool, bool) {
+	xLimits, yLimits := r.Limits()
+	if xLimits.Min() == xLimits.Max() || yLimits.Min() == yLimits.Max() {
+		return false, false // nothing overlaps an empty rectangle
+	}
+
+	if !cp.bounds.Overlaps(r) {
+		return false, false
+	}
+
+	// The polygon lies within the convex hull of its outer ring, so it's
+	// fully contained when every outer vertex is.
+	contained := true
+	for _, v := range cp.rings[0] {
+		if !r.ContainsPoint(v.X, v.Y) {
+			contained = false
+			break
+		}
+	}
+	if contained {
+		return true, true
+	}
+
+	// Any edge (outer or hole) touching the rectangle means overlap: the
+	// boundary is part of the polygon.
+	for _, ring := range cp.rings {
+		for i := range ring {
+			a, b := ring[i], ring[(i+1)%len(ring)]
+			if overlap, _ := lineOverlaps(a.X, a.Y, b.X, b.Y, r); overlap {
+				return true, false
+			}
+		}
+	}
+
+	// No edge touches the rectangle, so the rectangle lies entirely inside
+	// the polygon (the rectangle contains the polygon's edges, or is
+	// swallowed by a hole) or entirely outside of it. Any point of the
+	// rectangle will tell us which.
+	return cp.containsPoint(xLimits.Min(), yLimits.Min()), false
+}
+
+// IntersectsSegment indicates whether the segment touches the polygon's
+// boundary or lies within it.
+func (cp ColorPolygon) IntersectsSegment(x1, y1, x2, y2 int64) bool {
+	for _, ring := range cp.rings {
+		for i := range ring {
+			a, b := ring[i], ring[(i+1)%len(ring)]
+			if exact.SegmentsIntersect(a.X, a.Y, b.X, b.Y, x1, y1, x2, y2) {
+				return true
+			}
+		}
+	}
+
+	// No edge touches the segment, so it's entirely inside or outside.
+	return cp.containsPoint(x1, y1)
+}
+
+// containsPoint indicates whether (x,y), which must not lie on any edge, is
+// within the polygon.
+func (cp ColorPolygon) containsPoint(x, y int64) bool {
+	if !ringContainsPoint(cp.rings[0], x, y) {
+		return false
+	}
+
+	for _, hole := range cp.rings[1:] {
+		if ringContainsPoint(hole, x, y) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ringContainsPoint uses the even-odd rule to determine whether (x,y), which
+// must not lie on any edge, is within ring. It counts the edges crossed by a
+// ray cast from (x,y) toward +x, using exact orientation tests.
+func ringContainsPoint(ring []Vertex, x, y int64) bool {
+	var inside bool
+	for i := range ring {
+		a, b := ring[i], ring[(i+1)%len(ring)]
+		if (a.Y > y) == (b.Y > y) {
+			continue // edge doesn't span the ray
+		}
+
+		o := exact.Orientation(a.X, a.Y, b.X, b.Y, x, y)
+		if (b.Y > a.Y && o > 0) || (b.Y < a.Y && o < 0) {
+			inside = !inside // (x,y) is left of an upward edge or right of a downward one
+		}
+	}
+
+	return inside
+}
+
+func (cp ColorPolygon) pixelRings(v render.Viewport) [][][2]float64 {
+	result := make([][][2]float64, len(cp.rings))
+	for i, ring := range cp.rings {
+		result[i] = make([][2]float64, len(ring))
+		for j, vertex := range ring {
+			x, y := v.Point(vertex.X, vertex.Y)
+			result[i][j] = [2]float64{x, y}
+		}
+	}
+
+	return result
+}
+
+func (cp ColorPolygon) DrawRaster(dst draw.Image, v render.Viewport) {
+	render.FillPolygon(dst, cp.pixelRings(v), cp.color)
+}
+
+func (cp ColorPolygon) DrawSVG(w io.Writer, v render.Viewport) error {
+	fill, opacity := render.SVGColor(cp.color)
+	_, err := fmt.Fprintf(w, `<path d="%s" fill="%s" fill-opacity="%s" fill-rule="evenodd"/>`+"\n",
+		render.SVGPath(cp.pixelRings(v), true), fill, opacity)
+	return err
+}
+
+// NewColorPolygonE returns a ColorPolygon with the given outer ring and
+// holes. An error is returned if any ring has fewer than 3 vertices.
+func NewColorPolygonE(outer []Vertex, holes [][]Vertex, color color.RGBA) (ColorPolygon, error) {
+	if len(outer) < 3 {
+		return ColorPolygon{}, errors.New("polygon outer ring must have at least 3 vertices")
+	}
+
+	rings := make([][]Vertex, 0, len(holes)+1)
+	rings = append(rings, append([]Vertex(nil), outer...))
+	for i, hole := range holes {
+		if len(hole) < 3 {
+			return ColorPolygon{}, fmt.Errorf("polygon hole %d must have at least 3 vertices", i)
+		}
+		rings = append(rings, append([]Vertex(nil), hole...))
+	}
+
+	result := ColorPolygon{
+		rings:  rings,
+		bounds: boundingBox(verticesExtent(outer)),
+		color:  color,
+	}
+
+	result.computeHash()
+
+	return result, nil
+}
+
+// NewColorPolygon is a convenience wrapper around NewColorPolygonE which
+// panics rather than returning an error.
+func NewColorPolygon(outer []Vertex, holes [][]Vertex, color color.RGBA) ColorPolygon {
+	result, err := NewColorPolygonE(outer, holes, color)
+	if err != nil {
+		panic(err)
+	}
+
+	return result
+}
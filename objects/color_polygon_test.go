@@ -0,0 +1,280 @@
+package objects_test
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/chrismarget/two-dimensional-quad-tree/objects"
+	"github.com/chrismarget/two-dimensional-quad-tree/tdqt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColorPolygon_Overlaps(t *testing.T) {
+	// A 100x100 square with a 40x40 square hole in the middle.
+	//
+	//   (0,100) +-------------------+ (100,100)
+	//           |                   |
+	//           |  (30,70)  (70,70) |
+	//           |    +-------+      |
+	//           |    |  hole |      |
+	//           |    +-------+      |
+	//           |  (30,30)  (70,30) |
+	//           |                   |
+	//     (0,0) +-------------------+ (100,0)
+	square := []objects.Vertex{{0, 0}, {100, 0}, {100, 100}, {0, 100}}
+	hole := []objects.Vertex{{30, 30}, {70, 30}, {70, 70}, {30, 70}}
+	withHole := objects.NewColorPolygon(square, [][]objects.Vertex{hole}, color.RGBA{})
+
+	// A concave "U" shape.
+	u := objects.NewColorPolygon([]objects.Vertex{{0, 0}, {30, 0}, {30, 30}, {20, 30}, {20, 10}, {10, 10}, {10, 30}, {0, 30}}, nil, color.RGBA{})
+
+	type testCase struct {
+		polygon                objects.ColorPolygon
+		xMin, xMax, yMin, yMax int64
+		overlap                bool
+		fullyContained         bool
+	}
+
+	testCases := map[string]testCase{
+		"rect_contains_polygon":  {polygon: withHole, xMin: -10, xMax: 110, yMin: -10, yMax: 110, overlap: true, fullyContained: true},
+		"rect_in_solid_part":     {polygon: withHole, xMin: 5, xMax: 10, yMin: 5, yMax: 10, overlap: true},
+		"rect_in_hole":           {polygon: withHole, xMin: 40, xMax: 60, yMin: 40, yMax: 60},
+		"rect_crosses_hole_edge": {polygon: withHole, xMin: 25, xMax: 35, yMin: 40, yMax: 60, overlap: true},
+		"rect_touches_hole_edge": {polygon: withHole, xMin: 40, xMax: 60, yMin: 40, yMax: 71, overlap: true},
+		"rect_crosses_outer":     {polygon: withHole, xMin: -10, xMax: 10, yMin: 40, yMax: 60, overlap: true},
+		"rect_outside":           {polygon: withHole, xMin: 200, xMax: 210, yMin: 40, yMax: 60},
+		"rect_touches_max_edge":  {polygon: withHole, xMin: -10, xMax: 0, yMin: 40, yMax: 60},
+		"rect_touches_min_edge":  {polygon: withHole, xMin: 100, xMax: 110, yMin: 40, yMax: 60, overlap: true},
+		"rect_on_corner":         {polygon: withHole, xMin: 100, xMax: 110, yMin: 100, yMax: 110, overlap: true},
+		"rect_in_u_notch":        {polygon: u, xMin: 12, xMax: 18, yMin: 15, yMax: 40},
+		"rect_in_u_arm":          {polygon: u, xMin: 2, xMax: 8, yMin: 15, yMax: 25, overlap: true},
+		"rect_spans_u_notch":     {polygon: u, xMin: 5, xMax: 25, yMin: 35, yMax: 40},
+		"rect_spans_u":           {polygon: u, xMin: 5, xMax: 25, yMin: 15, yMax: 20, overlap: true},
+	}
+
+	for tName, tCase := range testCases {
+		t.Run(tName, func(t *testing.T) {
+			t.Parallel()
+
+			r := tdqt.NewRectangle(tdqt.NewLimits(tCase.xMin, tCase.xMax), tdqt.NewLimits(tCase.yMin, tCase.yMax))
+			overlap, fullyContained := tCase.polygon.Overlaps(r)
+			require.Equalf(t, tCase.overlap, overlap, "polygon %s should overlap rectangle %s", tCase.polygon.String(), r.String())
+			require.Equalf(t, tCase.fullyContained, fullyContained, "polygon %s should be fully contained by rectangle %s", tCase.polygon.String(), r.String())
+		})
+	}
+}
+
+func TestNewColorPolygonE(t *testing.T) {
+	_, err := objects.NewColorPolygonE([]objects.Vertex{{0, 0}, {1, 1}}, nil, color.RGBA{})
+	require.Error(t, err)
+
+	_, err = objects.NewColorPolygonE([]objects.Vertex{{0, 0}, {1, 1}, {1, 0}}, [][]objects.Vertex{{{0, 0}}}, color.RGBA{})
+	require.Error(t, err)
+
+	a := objects.NewColorPolygon([]objects.Vertex{{0, 0}, {10, 0}, {10, 10}}, [][]objects.Vertex{{{5, 1}, {9, 1}, {9, 5}}}, color.RGBA{})
+	b := objects.NewColorPolygon([]objects.Vertex{{0, 0}, {10, 0}, {10, 10}, {5, 1}, {9, 1}, {9, 5}}, nil, color.RGBA{})
+	require.NotEqual(t, a.Hash(), b.Hash())
+}
+
+// rasterScale is the number of ground truth samples per unit along each axis.
+const rasterScale = 4
+
+type testVertex struct{ x, y int64 }
+
+func cross(a, b, c testVertex) int64 {
+	return (b.x-a.x)*(c.y-a.y) - (b.y-a.y)*(c.x-a.x)
+}
+
+func onSegment(a, b, p testVertex) bool {
+	return cross(a, b, p) == 0 &&
+		min(a.x, b.x) <= p.x && p.x <= max(a.x, b.x) &&
+		min(a.y, b.y) <= p.y && p.y <= max(a.y, b.y)
+}
+
+func segmentsTouch(a, b, c, d testVertex) bool {
+	d1, d2 := cross(c, d, a), cross(c, d, b)
+	d3, d4 := cross(a, b, c), cross(a, b, d)
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+	return onSegment(c, d, a) || onSegment(c, d, b) || onSegment(a, b, c) || onSegment(a, b, d)
+}
+
+// evenOdd reports whether p, which must not be on the ring, is inside it.
+func evenOdd(ring []testVertex, p testVertex) bool {
+	var inside bool
+	for i := range ring {
+		a, b := ring[i], ring[(i+1)%len(ring)]
+		if (a.y > p.y) != (b.y > p.y) && float64(p.x) < float64(a.x)+float64(p.y-a.y)*float64(b.x-a.x)/float64(b.y-a.y) {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func onRing(ring []testVertex, p testVertex) bool {
+	for i := range ring {
+		if onSegment(ring[i], ring[(i+1)%len(ring)], p) {
+			return true
+		}
+	}
+	return false
+}
+
+// inPolygon reports whether p is within the (closed) polygon described by
+// rings.
+func inPolygon(rings [][]testVertex, p testVertex) bool {
+	for _, ring := range rings {
+		if onRing(ring, p) {
+			return true
+		}
+	}
+	if !evenOdd(rings[0], p) {
+		return false
+	}
+	for _, hole := range rings[1:] {
+		if evenOdd(hole, p) {
+			return false
+		}
+	}
+	return true
+}
+
+// validHole reports whether hole lies strictly within outer without touching
+// it.
+func validHole(outer, hole []testVertex) bool {
+	for _, v := range hole {
+		if onRing(outer, v) || !evenOdd(outer, v) {
+			return false
+		}
+	}
+	for i := range hole {
+		for j := range outer {
+			if segmentsTouch(hole[i], hole[(i+1)%len(hole)], outer[j], outer[(j+1)%len(outer)]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func FuzzColorPolygon_Overlaps(f *testing.F) {
+	f.Add([]byte{1, 0, 0, 80, 0, 80, 80, 0, 80, 0, 24, 24, 56, 24, 56, 56, 40, 40, 48, 48}, int64(0), int64(0))
+	f.Add([]byte{4, 0, 0, 120, 0, 120, 120, 0, 120, 60, 60, 8, 8, 64, 8, 32, 64, 0, 16, 0, 16}, int64(1<<60), int64(-1<<60))
+	f.Add([]byte{0, 0, 0, 120, 0, 0, 120, 120, 121, 0, 8, 0, 8}, int64(math.MaxInt64), int64(math.MinInt64))
+
+	f.Fuzz(func(t *testing.T, data []byte, xOffset, yOffset int64) {
+		next := func() int64 { // small signed coordinate in [-16, 15]
+			if len(data) == 0 {
+				return 0
+			}
+			b := data[0]
+			data = data[1:]
+			return int64(int8(b) >> 3)
+		}
+		ring := func(n int) []testVertex {
+			result := make([]testVertex, n)
+			for i := range result {
+				result[i] = testVertex{next(), next()}
+			}
+			return result
+		}
+
+		if len(data) == 0 {
+			return
+		}
+		n := 3 + int(data[0]%6)
+		data = data[1:]
+		rings := [][]testVertex{ring(n)}
+
+		if len(data) > 0 && data[0]%2 == 1 {
+			n = 3 + int(data[0]%4)
+			data = data[1:]
+			if hole := ring(n); validHole(rings[0], hole) {
+				rings = append(rings, hole)
+			}
+		}
+
+		xMin, xMax, yMin, yMax := next(), next(), next(), next()
+		xMin, xMax = min(xMin, xMax), max(xMin, xMax)
+		yMin, yMax = min(yMin, yMax), max(yMin, yMax)
+
+		// Translating everything leaves the answer unchanged, and lets us
+		// exercise coordinates far beyond float64 precision.
+		xOffset = min(max(xOffset, math.MinInt64+32), math.MaxInt64-32)
+		yOffset = min(max(yOffset, math.MinInt64+32), math.MaxInt64-32)
+
+		shifted := make([][]objects.Vertex, len(rings))
+		for i, ring := range rings {
+			for _, v := range ring {
+				shifted[i] = append(shifted[i], objects.Vertex{X: v.x + xOffset, Y: v.y + yOffset})
+			}
+		}
+		polygon := objects.NewColorPolygon(shifted[0], shifted[1:], color.RGBA{})
+		r := tdqt.NewRectangle(tdqt.NewLimits(xMin+xOffset, xMax+xOffset), tdqt.NewLimits(yMin+yOffset, yMax+yOffset))
+		overlap, contained := polygon.Overlaps(r)
+
+		// rasterize the polygon within the rectangle
+		scaled := make([][]testVertex, len(rings))
+		for i, ring := range rings {
+			for _, v := range ring {
+				scaled[i] = append(scaled[i], testVertex{v.x * rasterScale, v.y * rasterScale})
+			}
+		}
+		var hit bool
+		for x := xMin * rasterScale; x < xMax*rasterScale && !hit; x++ {
+			for y := yMin * rasterScale; y < yMax*rasterScale && !hit; y++ {
+				hit = inPolygon(scaled, testVertex{x, y})
+			}
+		}
+
+		// Overlaps too thin to show up in the raster must be due to an edge
+		// grazing the rectangle.
+		var edgeOverlap bool
+		for _, ring := range rings {
+			for i := range ring {
+				a, b := ring[i], ring[(i+1)%len(ring)]
+				if o, _ := bruteForceLineOverlaps(a.x, a.y, b.x, b.y, xMin, xMax, yMin, yMax); o {
+					edgeOverlap = true
+				}
+			}
+		}
+
+		require.Equalf(t, hit || edgeOverlap, overlap, "polygon %s overlap rectangle %s", polygon, r)
+
+		expContained := xMin < xMax && yMin < yMax
+		for _, v := range rings[0] {
+			expContained = expContained && xMin <= v.x && v.x < xMax && yMin <= v.y && v.y < yMax
+		}
+		require.Equalf(t, expContained, contained, "polygon %s contained by rectangle %s", polygon, r)
+	})
+}
+
+func TestColorPolygon_IntersectsSegment(t *testing.T) {
+	square := []objects.Vertex{{0, 0}, {100, 0}, {100, 100}, {0, 100}}
+	hole := []objects.Vertex{{30, 30}, {70, 30}, {70, 70}, {30, 70}}
+	withHole := objects.NewColorPolygon(square, [][]objects.Vertex{hole}, color.RGBA{})
+
+	testCases := map[string]struct {
+		seg       [4]int64
+		intersect bool
+	}{
+		"inside":          {seg: [4]int64{5, 5, 20, 10}, intersect: true},
+		"within_hole":     {seg: [4]int64{40, 40, 60, 60}},
+		"outside":         {seg: [4]int64{110, 0, 150, 100}},
+		"crosses_outer":   {seg: [4]int64{-10, 50, 10, 50}, intersect: true},
+		"crosses_hole":    {seg: [4]int64{20, 50, 50, 50}, intersect: true},
+		"touches_corner":  {seg: [4]int64{100, 100, 120, 120}, intersect: true},
+		"along_hole_edge": {seg: [4]int64{30, 40, 30, 60}, intersect: true},
+		"spans_the_hole":  {seg: [4]int64{50, 10, 50, 90}, intersect: true},
+	}
+
+	for tName, tCase := range testCases {
+		t.Run(tName, func(t *testing.T) {
+			s := tCase.seg
+			require.Equal(t, tCase.intersect, withHole.IntersectsSegment(s[0], s[1], s[2], s[3]))
+			require.Equal(t, tCase.intersect, withHole.IntersectsSegment(s[2], s[3], s[0], s[1]))
+		})
+	}
+}
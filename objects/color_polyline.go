@@ -0,0 +1,192 @@
+package objects
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image/color"
+	"image/draw"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/chrismarget/two-dimensional-quad-tree/internal/exact"
+	"github.com/chrismarget/two-dimensional-quad-tree/render"
+	"github.com/chrismarget/two-dimensional-quad-tree/tdqt"
+)
+
+var (
+	_ tdqt.Object             = (*ColorPolyline)(nil)
+	_ tdqt.Anchored           = (*ColorPolyline)(nil)
+	_ tdqt.Equaler            = (*ColorPolyline)(nil)
+	_ tdqt.Bounded            = (*ColorPolyline)(nil)
+	_ tdqt.SegmentIntersector = (*ColorPolyline)(nil)
+	_ render.RasterDrawer     = (*ColorPolyline)(nil)
+	_ render.SVGDrawer        = (*ColorPolyline)(nil)
+)
+
+// ColorPolyline is an open path of connected line segments with a color,
+// such as a GPS track or a road. Consecutive vertices are joined by a
+// segment; the last vertex is not joined back to the first. The polyline is
+// stored in the tree as a single object, so searches return the whole path
+// rather than individual segments.
+type ColorPolyline struct {
+	vertices []Vertex
+	bounds   tdqt.Rectangle
+	color    color.RGBA
+	hash     uint64
+}
+
+func (cp ColorPolyline) Anchor() (int64, int64) {
+	return cp.bounds.Center()
+}
+
+func (cp ColorPolyline) Bounds() tdqt.Rectangle {
+	return cp.bounds
+}
+
+func (cp ColorPolyline) Hash() uint64 {
+	return cp.hash
+}
+
+func (cp ColorPolyline) Color() color.RGBA {
+	return cp.color
+}
+
+func (cp ColorPolyline) String() string {
+	var sb strings.Builder
+	for i, v := range cp.vertices {
+		if i > 0 {
+			sb.WriteString("->")
+		}
+		sb.WriteString(v.String())
+	}
+
+	return fmt.Sprintf("%s: (%d,%d,%d,%d)", sb.String(), cp.color.R, cp.color.G, cp.color.B, cp.color.A)
+}
+
+func (cp ColorPolyline) Equal(o tdqt.Object) bool {
+	other, ok := o.(ColorPolyline)
+	return ok && cp.color == other.color && slices.Equal(cp.vertices, other.vertices)
+}
+
+func (cp *ColorPolyline) computeHash() {
+	cp.hash = FnvHash(cp.appendHashInput(nil, tdqt.Object.Hash))
+}
+
+// appendHashInput appends the bytes which identify the ColorPolyline to b.
+func (cp ColorPolyline) appendHashInput(b []byte, _ tdqt.HashFunc) []byte {
+	bytes := slices.Grow(b, 16*len(cp.vertices)+4)
+	for _, v := range cp.vertices {
+		bytes = binary.BigEndian.AppendUint64(bytes, uint64(v.X))
+		bytes = binary.BigEndian.AppendUint64(bytes, uint64(v.Y))
+	}
+	bytes = append(bytes, cp.color.R, cp.color.G, cp.color.B, cp.color.A)
+
+	return bytes
+}
+
+func (cp ColorPolyline) Overlaps(r tdqt.Rectangle) (bool, bool) {
+	xLimits, yLimits := r.Limits()
+	if xLimits.Min() == xLimits.Max() || yLimits.Min() == yLimits.Max() {
+		return false, false // nothing overlaps an empty rectangle
+	}
+
+	if !cp.bounds.Overlaps(r) {
+		return false, false
+	}
+
+	// Rectangles are convex, so the polyline is fully contained when every
+	// vertex is.
+	contained := true
+	for _, v := range cp.vertices {
+		if !r.ContainsPoint(v.X, v.Y) {
+			contained = false
+			break
+		}
+	}
+	if contained {
+		return true, true
+	}
+
+	for i := range cp.vertices[1:] {
+		a, b := cp.vertices[i], cp.vertices[i+1]
+
+		// Skip segments whose bounding box misses the rectangle before
+		// doing any real work.
+		if max(a.X, b.X) < xLimits.Min() || min(a.X, b.X) >= xLimits.Max() ||
+			max(a.Y, b.Y) < yLimits.Min() || min(a.Y, b.Y) >= yLimits.Max() {
+			continue
+		}
+
+		if overlap, _ := lineOverlaps(a.X, a.Y, b.X, b.Y, r); overlap {
+			return true, false // no need to look at the remaining segments
+		}
+	}
+
+	return false, false
+}
+
+func (cp ColorPolyline) IntersectsSegment(x1, y1, x2, y2 int64) bool {
+	for i := 1; i < len(cp.vertices); i++ {
+		a, b := cp.vertices[i-1], cp.vertices[i]
+		if exact.SegmentsIntersect(a.X, a.Y, b.X, b.Y, x1, y1, x2, y2) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (cp ColorPolyline) pixelPath(v render.Viewport) [][2]float64 {
+	result := make([][2]float64, len(cp.vertices))
+	for i, vertex := range cp.vertices {
+		x, y := v.Point(vertex.X, vertex.Y)
+		result[i] = [2]float64{x, y}
+	}
+
+	return result
+}
+
+func (cp ColorPolyline) DrawRaster(dst draw.Image, v render.Viewport) {
+	path := cp.pixelPath(v)
+	for i := range path[1:] {
+		render.Line(dst, path[i][0], path[i][1], path[i+1][0], path[i+1][1], cp.color)
+	}
+}
+
+func (cp ColorPolyline) DrawSVG(w io.Writer, v render.Viewport) error {
+	stroke, opacity := render.SVGColor(cp.color)
+	_, err := fmt.Fprintf(w, `<path d="%s" fill="none" stroke="%s" stroke-opacity="%s"/>`+"\n",
+		render.SVGPath([][][2]float64{cp.pixelPath(v)}, false), stroke, opacity)
+	return err
+}
+
+// NewColorPolylineE returns a ColorPolyline which visits vertices in order.
+// An error is returned if fewer than 2 vertices are supplied.
+func NewColorPolylineE(vertices []Vertex, color color.RGBA) (ColorPolyline, error) {
+	if len(vertices) < 2 {
+		return ColorPolyline{}, errors.New("polyline must have at least 2 vertices")
+	}
+
+	result := ColorPolyline{
+		vertices: append([]Vertex(nil), vertices...),
+		bounds:   boundingBox(verticesExtent(vertices)),
+		color:    color,
+	}
+
+	result.computeHash()
+
+	return result, nil
+}
+
+// NewColorPolyline is a convenience wrapper around NewColorPolylineE which
+// panics rather than returning an error.
+func NewColorPolyline(vertices []Vertex, color color.RGBA) ColorPolyline {
+	result, err := NewColorPolylineE(vertices, color)
+	if err != nil {
+		panic(err)
+	}
+
+	return result
+}
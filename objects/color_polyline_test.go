@@ -0,0 +1,88 @@
+package objects_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/chrismarget/two-dimensional-quad-tree/objects"
+	"github.com/chrismarget/two-dimensional-quad-tree/tdqt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColorPolyline_Overlaps(t *testing.T) {
+	// A "Z" shaped track.
+	//
+	//   (0,100) +-------------------+ (100,100)
+	//                             /
+	//                           /
+	//                         /
+	//                       /
+	//                     /
+	//     (0,0) +-------------------+ (100,0)
+	z := objects.NewColorPolyline([]objects.Vertex{{0, 100}, {100, 100}, {0, 0}, {100, 0}}, color.RGBA{})
+
+	type testCase struct {
+		xMin, xMax, yMin, yMax int64
+		overlap                bool
+		fullyContained         bool
+	}
+
+	testCases := map[string]testCase{
+		"rect_contains_track":   {xMin: -10, xMax: 110, yMin: -10, yMax: 110, overlap: true, fullyContained: true},
+		"rect_cuts_diagonal":    {xMin: -10, xMax: 100, yMin: -10, yMax: 100, overlap: true},
+		"rect_misses_max_edge":  {xMin: 100, xMax: 110, yMin: 10, yMax: 90},
+		"rect_on_first_segment": {xMin: 40, xMax: 60, yMin: 95, yMax: 105, overlap: true},
+		"rect_on_diagonal":      {xMin: 45, xMax: 55, yMin: 45, yMax: 55, overlap: true},
+		"rect_on_last_segment":  {xMin: 40, xMax: 60, yMin: -5, yMax: 5, overlap: true},
+		"rect_beside_diagonal":  {xMin: 10, xMax: 20, yMin: 40, yMax: 60},
+		"rect_in_bbox_only":     {xMin: 70, xMax: 90, yMin: 10, yMax: 30},
+		"rect_on_vertex":        {xMin: 100, xMax: 110, yMin: 100, yMax: 110, overlap: true},
+		"rect_outside":          {xMin: 200, xMax: 210, yMin: 40, yMax: 60},
+		"rect_empty":            {xMin: 50, xMax: 50, yMin: 0, yMax: 100},
+	}
+
+	for tName, tCase := range testCases {
+		t.Run(tName, func(t *testing.T) {
+			t.Parallel()
+
+			r := tdqt.NewRectangle(tdqt.NewLimits(tCase.xMin, tCase.xMax), tdqt.NewLimits(tCase.yMin, tCase.yMax))
+			overlap, fullyContained := z.Overlaps(r)
+			require.Equalf(t, tCase.overlap, overlap, "polyline %s should overlap rectangle %s", z.String(), r.String())
+			require.Equalf(t, tCase.fullyContained, fullyContained, "polyline %s should be fully contained by rectangle %s", z.String(), r.String())
+		})
+	}
+}
+
+func TestNewColorPolylineE(t *testing.T) {
+	_, err := objects.NewColorPolylineE([]objects.Vertex{{0, 0}}, color.RGBA{})
+	require.Error(t, err)
+
+	a := objects.NewColorPolyline([]objects.Vertex{{0, 0}, {10, 0}, {10, 10}}, color.RGBA{})
+	b := objects.NewColorPolyline([]objects.Vertex{{0, 0}, {10, 0}, {10, 11}}, color.RGBA{})
+	c := objects.NewColorPolyline([]objects.Vertex{{10, 10}, {10, 0}, {0, 0}}, color.RGBA{})
+	require.NotEqual(t, a.Hash(), b.Hash())
+	require.NotEqual(t, a.Hash(), c.Hash())
+}
+
+func TestColorPolyline_Search(t *testing.T) {
+	tree := tdqt.NewTree(tdqt.NewRectangle(tdqt.NewLimits(0, 1024), tdqt.NewLimits(0, 1024)), tdqt.WithMaxObjects(1))
+
+	// A zig-zag track spanning the whole tree, plus enough points to force
+	// subdivision so that the track is stored in many leaves.
+	var vertices []objects.Vertex
+	for i := int64(0); i <= 16; i++ {
+		vertices = append(vertices, objects.Vertex{X: i * 60, Y: (i % 2) * 1000})
+	}
+	track := objects.NewColorPolyline(vertices, color.RGBA{})
+	tree.Insert(track)
+	for i := int64(0); i < 32; i++ {
+		tree.Insert(objects.NewColorPoint(i*32, 1023-i*32, color.RGBA{}))
+	}
+
+	result := tree.Search(tdqt.NewRectangle(tdqt.NewLimits(0, 1024), tdqt.NewLimits(0, 1024)))
+	require.Len(t, result, 33)
+	require.Contains(t, result, track.Hash())
+
+	result = tree.Search(tdqt.NewRectangle(tdqt.NewLimits(500, 520), tdqt.NewLimits(500, 520)))
+	require.Contains(t, result, track.Hash())
+}
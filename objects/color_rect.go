@@ -0,0 +1,115 @@
+package objects
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image/color"
+	"image/draw"
+	"io"
+	"slices"
+
+	"github.com/chrismarget/two-dimensional-quad-tree/render"
+	"github.com/chrismarget/two-dimensional-quad-tree/tdqt"
+)
+
+var (
+	_ tdqt.Object             = (*ColorRect)(nil)
+	_ tdqt.Anchored           = (*ColorRect)(nil)
+	_ tdqt.Equaler            = (*ColorRect)(nil)
+	_ tdqt.Bounded            = (*ColorRect)(nil)
+	_ tdqt.SegmentIntersector = (*ColorRect)(nil)
+	_ render.RasterDrawer     = (*ColorRect)(nil)
+	_ render.SVGDrawer        = (*ColorRect)(nil)
+)
+
+// ColorRect is an axis-aligned, filled rectangle with a color. Like
+// tdqt.Rectangle, it is half-open: it covers its minimum coordinates, but not
+// its maximum coordinates.
+type ColorRect struct {
+	rect  tdqt.Rectangle
+	color color.RGBA
+	hash  uint64
+}
+
+func (cr ColorRect) Anchor() (int64, int64) {
+	return cr.rect.Center()
+}
+
+func (cr ColorRect) Bounds() tdqt.Rectangle {
+	return cr.rect
+}
+
+func (cr ColorRect) Hash() uint64 {
+	return cr.hash
+}
+
+func (cr ColorRect) Color() color.RGBA {
+	return cr.color
+}
+
+func (cr ColorRect) String() string {
+	return fmt.Sprintf("%s: (%d,%d,%d,%d)", cr.rect.String(), cr.color.R, cr.color.G, cr.color.B, cr.color.A)
+}
+
+func (cr ColorRect) Equal(o tdqt.Object) bool {
+	other, ok := o.(ColorRect)
+	return ok && cr.rect.Equal(other.rect) && cr.color == other.color
+}
+
+func (cr *ColorRect) computeHash() {
+	cr.hash = FnvHash(cr.appendHashInput(nil, tdqt.Object.Hash))
+}
+
+// appendHashInput appends the bytes which identify the ColorRect to b.
+func (cr ColorRect) appendHashInput(b []byte, _ tdqt.HashFunc) []byte {
+	xLimits, yLimits := cr.rect.Limits()
+
+	bytes := slices.Grow(b, 36)
+	bytes = binary.BigEndian.AppendUint64(bytes, uint64(xLimits.Min()))
+	bytes = binary.BigEndian.AppendUint64(bytes, uint64(xLimits.Max()))
+	bytes = binary.BigEndian.AppendUint64(bytes, uint64(yLimits.Min()))
+	bytes = binary.BigEndian.AppendUint64(bytes, uint64(yLimits.Max()))
+	bytes = append(bytes, cr.color.R, cr.color.G, cr.color.B, cr.color.A)
+
+	return bytes
+}
+
+func (cr ColorRect) Overlaps(r tdqt.Rectangle) (bool, bool) {
+	x, y := cr.rect.Limits()
+	rx, ry := r.Limits()
+
+	if !x.Overlaps(rx) || !y.Overlaps(ry) {
+		return false, false // also covers empty rectangles
+	}
+
+	return true, rx.ContainsLimits(x) && ry.ContainsLimits(y)
+}
+
+func (cr ColorRect) IntersectsSegment(x1, y1, x2, y2 int64) bool {
+	overlap, _ := lineOverlaps(x1, y1, x2, y2, cr.rect)
+	return overlap
+}
+
+func (cr ColorRect) DrawRaster(dst draw.Image, v render.Viewport) {
+	x, y := cr.rect.Limits()
+	render.FillRect(dst, v.X(x.Min()), v.Y(y.Max()), v.X(x.Max()), v.Y(y.Min()), cr.color)
+}
+
+func (cr ColorRect) DrawSVG(w io.Writer, v render.Viewport) error {
+	x, y := cr.rect.Limits()
+	fill, opacity := render.SVGColor(cr.color)
+	_, err := fmt.Fprintf(w, `<rect x="%g" y="%g" width="%g" height="%g" fill="%s" fill-opacity="%s"/>`+"\n",
+		v.X(x.Min()), v.Y(y.Max()), v.X(x.Max())-v.X(x.Min()), v.Y(y.Min())-v.Y(y.Max()), fill, opacity)
+	return err
+}
+
+func NewColorRect(r tdqt.Rectangle, color color.RGBA) ColorRect {
+	result := ColorRect{
+		rect:  r,
+		color: color,
+	}
+
+	result.computeHash()
+
+	return result
+}
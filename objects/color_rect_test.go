@@ -0,0 +1,63 @@
+package objects_test
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/chrismarget/two-dimensional-quad-tree/objects"
+	"github.com/chrismarget/two-dimensional-quad-tree/tdqt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColorRect_Overlaps(t *testing.T) {
+	limitsTenTwenty := tdqt.NewLimits(10, 20)
+	testRectangle := tdqt.NewRectangle(limitsTenTwenty, limitsTenTwenty)
+
+	type testCase struct {
+		xMin, xMax, yMin, yMax int64
+		overlap                bool
+		fullyContained         bool
+	}
+
+	testCases := map[string]testCase{
+		"inside":         {xMin: 12, xMax: 18, yMin: 12, yMax: 18, overlap: true, fullyContained: true},
+		"same":           {xMin: 10, xMax: 20, yMin: 10, yMax: 20, overlap: true, fullyContained: true},
+		"surrounding":    {xMin: 0, xMax: 30, yMin: 0, yMax: 30, overlap: true},
+		"corner":         {xMin: 5, xMax: 11, yMin: 5, yMax: 11, overlap: true},
+		"crossing":       {xMin: 0, xMax: 30, yMin: 14, yMax: 16, overlap: true},
+		"touching_right": {xMin: 20, xMax: 30, yMin: 10, yMax: 20},
+		"touching_top":   {xMin: 10, xMax: 20, yMin: 20, yMax: 30},
+		"touching_left":  {xMin: 0, xMax: 10, yMin: 10, yMax: 20},
+		"touching_below": {xMin: 10, xMax: 20, yMin: 0, yMax: 10},
+		"last_column":    {xMin: 19, xMax: 30, yMin: 10, yMax: 20, overlap: true},
+		"disjoint":       {xMin: 50, xMax: 60, yMin: 50, yMax: 60},
+		"empty_inside":   {xMin: 15, xMax: 15, yMin: 10, yMax: 20},
+		"full":           {xMin: math.MinInt64, xMax: math.MaxInt64, yMin: math.MinInt64, yMax: math.MaxInt64, overlap: true},
+	}
+
+	for tName, tCase := range testCases {
+		t.Run(tName, func(t *testing.T) {
+			t.Parallel()
+
+			cr := objects.NewColorRect(
+				tdqt.NewRectangle(tdqt.NewLimits(tCase.xMin, tCase.xMax), tdqt.NewLimits(tCase.yMin, tCase.yMax)),
+				color.RGBA{},
+			)
+			overlap, fullyContained := cr.Overlaps(testRectangle)
+			require.Equalf(t, tCase.overlap, overlap, "rect %s should overlap rectangle %s", cr.String(), testRectangle.String())
+			require.Equalf(t, tCase.fullyContained, fullyContained, "rect %s should be fully contained by rectangle %s", cr.String(), testRectangle.String())
+		})
+	}
+}
+
+func TestColorRect_Hash(t *testing.T) {
+	r := tdqt.NewRectangle(tdqt.NewLimits(0, 10), tdqt.NewLimits(0, 10))
+	a := objects.NewColorRect(r, color.RGBA{R: 1})
+	b := objects.NewColorRect(r, color.RGBA{R: 2})
+	c := objects.NewColorRect(tdqt.NewRectangle(tdqt.NewLimits(0, 10), tdqt.NewLimits(0, 11)), color.RGBA{R: 1})
+
+	require.Equal(t, a.Hash(), objects.NewColorRect(r, color.RGBA{R: 1}).Hash())
+	require.NotEqual(t, a.Hash(), b.Hash())
+	require.NotEqual(t, a.Hash(), c.Hash())
+}
@@ -0,0 +1,164 @@
+package objects
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image/draw"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/chrismarget/two-dimensional-quad-tree/render"
+	"github.com/chrismarget/two-dimensional-quad-tree/tdqt"
+)
+
+var (
+	_ tdqt.Object             = (*Composite)(nil)
+	_ tdqt.Equaler            = (*Composite)(nil)
+	_ tdqt.SegmentIntersector = (*Composite)(nil)
+	_ render.RasterDrawer     = (*Composite)(nil)
+	_ render.SVGDrawer        = (*Composite)(nil)
+)
+
+// Composite is a single feature made of several member Objects, such as a
+// road made of several polylines, or a site made of a polygon and some
+// marker points. It is stored in the tree under a single Hash, so searches
+// return the feature once rather than once per member.
+type Composite struct {
+	members []tdqt.Object
+	hash    uint64
+}
+
+func (c Composite) Hash() uint64 {
+	return c.hash
+}
+
+// Members returns the Objects which make up the Composite, in the order they
+// were supplied.
+func (c Composite) Members() []tdqt.Object {
+	return append([]tdqt.Object(nil), c.members...)
+}
+
+func (c Composite) String() string {
+	parts := make([]string, len(c.members))
+	for i, m := range c.members {
+		parts[i] = fmt.Sprint(m)
+	}
+
+	return "{" + strings.Join(parts, "; ") + "}"
+}
+
+// Equal reports whether o is a Composite with equal members, in the same
+// order. Members which don't implement tdqt.Equaler are compared by Hash.
+func (c Composite) Equal(o tdqt.Object) bool {
+	other, ok := o.(Composite)
+	return ok && slices.EqualFunc(c.members, other.members, func(a, b tdqt.Object) bool {
+		if a.Hash() != b.Hash() {
+			return false
+		}
+		if e, ok := a.(tdqt.Equaler); ok {
+			return e.Equal(b)
+		}
+		return true
+	})
+}
+
+func (c *Composite) computeHash() {
+	c.hash = FnvHash(c.appendHashInput(nil, tdqt.Object.Hash))
+}
+
+// appendHashInput appends the bytes which identify the Composite to b. The
+// members are represented by their hashes, as computed by hash.
+func (c Composite) appendHashInput(b []byte, hash tdqt.HashFunc) []byte {
+	bytes := slices.Grow(b, 8*len(c.members))
+	for _, m := range c.members {
+		bytes = binary.BigEndian.AppendUint64(bytes, hash(m))
+	}
+
+	return bytes
+}
+
+// Overlaps indicates that the Composite overlaps r when any member does, and
+// that it's fully contained by r only when every member is.
+func (c Composite) Overlaps(r tdqt.Rectangle) (bool, bool) {
+	overlap, contained := false, true
+	for _, m := range c.members {
+		o, fc := m.Overlaps(r)
+		overlap = overlap || o
+		contained = contained && fc
+		if overlap && !contained {
+			break // neither answer can change now
+		}
+	}
+
+	return overlap, overlap && contained
+}
+
+// IntersectsSegment indicates whether any member which implements
+// tdqt.SegmentIntersector intersects the segment.
+func (c Composite) IntersectsSegment(x1, y1, x2, y2 int64) bool {
+	for _, m := range c.members {
+		if si, ok := m.(tdqt.SegmentIntersector); ok && si.IntersectsSegment(x1, y1, x2, y2) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DrawRaster draws each member which implements render.RasterDrawer.
+func (c Composite) DrawRaster(dst draw.Image, v render.Viewport) {
+	for _, m := range c.members {
+		if rd, ok := m.(render.RasterDrawer); ok {
+			rd.DrawRaster(dst, v)
+		}
+	}
+}
+
+// DrawSVG draws each member which implements render.SVGDrawer.
+func (c Composite) DrawSVG(w io.Writer, v render.Viewport) error {
+	for _, m := range c.members {
+		if sd, ok := m.(render.SVGDrawer); ok {
+			if err := sd.DrawSVG(w, v); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// NewCompositeE returns a Composite made of members. The Composite's Hash
+// depends on the members' hashes and their order. An error is returned if
+// no members are supplied, or if any member is nil.
+func NewCompositeE(members ...tdqt.Object) (Composite, error) {
+	if len(members) == 0 {
+		return Composite{}, errors.New("composite must have at least 1 member")
+	}
+
+	for i, m := range members {
+		if m == nil {
+			return Composite{}, fmt.Errorf("composite member %d is nil", i)
+		}
+	}
+
+	result := Composite{
+		members: append([]tdqt.Object(nil), members...),
+	}
+
+	result.computeHash()
+
+	return result, nil
+}
+
+// NewComposite is a convenience wrapper around NewCompositeE which panics
+// rather than returning an error.
+func NewComposite(members ...tdqt.Object) Composite {
+	result, err := NewCompositeE(members...)
+	if err != nil {
+		panic(err)
+	}
+
+	return result
+}
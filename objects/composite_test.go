@@ -0,0 +1,81 @@
+package objects_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/chrismarget/two-dimensional-quad-tree/objects"
+	"github.com/chrismarget/two-dimensional-quad-tree/tdqt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComposite_Overlaps(t *testing.T) {
+	// A site: a square plot with a marker point well outside of it.
+	site := objects.NewComposite(
+		objects.NewColorRect(tdqt.NewRectangle(tdqt.NewLimits(0, 10), tdqt.NewLimits(0, 10)), color.RGBA{}),
+		objects.NewColorPoint(50, 50, color.RGBA{}),
+	)
+
+	type testCase struct {
+		xMin, xMax, yMin, yMax int64
+		overlap                bool
+		fullyContained         bool
+	}
+
+	testCases := map[string]testCase{
+		"contains_all":    {xMin: -10, xMax: 60, yMin: -10, yMax: 60, overlap: true, fullyContained: true},
+		"contains_plot":   {xMin: -10, xMax: 20, yMin: -10, yMax: 20, overlap: true},
+		"contains_marker": {xMin: 40, xMax: 60, yMin: 40, yMax: 60, overlap: true},
+		"crosses_plot":    {xMin: 5, xMax: 20, yMin: 5, yMax: 20, overlap: true},
+		"between":         {xMin: 20, xMax: 40, yMin: 20, yMax: 40},
+	}
+
+	for tName, tCase := range testCases {
+		t.Run(tName, func(t *testing.T) {
+			t.Parallel()
+
+			r := tdqt.NewRectangle(tdqt.NewLimits(tCase.xMin, tCase.xMax), tdqt.NewLimits(tCase.yMin, tCase.yMax))
+			overlap, fullyContained := site.Overlaps(r)
+			require.Equalf(t, tCase.overlap, overlap, "composite %s should overlap rectangle %s", site.String(), r.String())
+			require.Equalf(t, tCase.fullyContained, fullyContained, "composite %s should be fully contained by rectangle %s", site.String(), r.String())
+		})
+	}
+}
+
+func TestNewCompositeE(t *testing.T) {
+	_, err := objects.NewCompositeE()
+	require.Error(t, err)
+
+	_, err = objects.NewCompositeE(objects.NewColorPoint(0, 0, color.RGBA{}), nil)
+	require.Error(t, err)
+
+	a := objects.NewColorPoint(0, 0, color.RGBA{})
+	b := objects.NewColorPoint(1, 1, color.RGBA{})
+	require.NotEqual(t, objects.NewComposite(a, b).Hash(), objects.NewComposite(b, a).Hash())
+	require.NotEqual(t, objects.NewComposite(a).Hash(), objects.NewComposite(a, a).Hash())
+	require.Len(t, objects.NewComposite(a, b).Members(), 2)
+}
+
+func TestComposite_Search(t *testing.T) {
+	tree := tdqt.NewTree(tdqt.NewRectangle(tdqt.NewLimits(0, 1024), tdqt.NewLimits(0, 1024)), tdqt.WithMaxObjects(1))
+
+	// A road made of two polylines at opposite corners of the tree.
+	road := objects.NewComposite(
+		objects.NewColorPolyline([]objects.Vertex{{X: 10, Y: 10}, {X: 100, Y: 10}, {X: 100, Y: 100}}, color.RGBA{}),
+		objects.NewColorPolyline([]objects.Vertex{{X: 900, Y: 900}, {X: 1000, Y: 1000}}, color.RGBA{}),
+	)
+	tree.Insert(road)
+	for i := int64(0); i < 32; i++ {
+		tree.Insert(objects.NewColorPoint(i*32, 1023-i*32, color.RGBA{}))
+	}
+
+	result := tree.Search(tdqt.NewRectangle(tdqt.NewLimits(0, 1024), tdqt.NewLimits(0, 1024)))
+	require.Len(t, result, 33)
+	require.Contains(t, result, road.Hash())
+
+	result = tree.Search(tdqt.NewRectangle(tdqt.NewLimits(950, 960), tdqt.NewLimits(950, 960)))
+	require.Contains(t, result, road.Hash())
+
+	result = tree.Search(tdqt.NewRectangle(tdqt.NewLimits(500, 510), tdqt.NewLimits(400, 410)))
+	require.NotContains(t, result, road.Hash())
+}
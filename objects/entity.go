@@ -0,0 +1,140 @@
+package objects
+
+import (
+	"fmt"
+	"image/draw"
+	"io"
+
+	"github.com/chrismarget/two-dimensional-quad-tree/render"
+	"github.com/chrismarget/two-dimensional-quad-tree/tdqt"
+)
+
+var (
+	_ Entity = entity{}
+	_ Entity = anchoredEntity{}
+	_ Entity = boundedEntity{}
+	_ Entity = anchoredBoundedEntity{}
+
+	_ tdqt.Anchored = anchoredEntity{}
+	_ tdqt.Bounded  = boundedEntity{}
+	_ tdqt.Anchored = anchoredBoundedEntity{}
+	_ tdqt.Bounded  = anchoredBoundedEntity{}
+)
+
+// Entity attaches a stable, caller-supplied ID to an Object, so that the tree
+// treats successive versions of it (after it moves or changes color, say) as
+// the same thing. Insert or Update the tree with a new Entity carrying the
+// same ID to replace the old version, or Remove the ID to delete it.
+//
+// An Entity implements tdqt.Anchored and tdqt.Bounded whenever the wrapped
+// Object does, so wrapping an Object doesn't hide it from data-driven split
+// strategies or from the tree's bounding box checks.
+type Entity interface {
+	tdqt.Object
+	tdqt.Identified
+	tdqt.SegmentIntersector
+	render.RasterDrawer
+	render.SVGDrawer
+
+	// Object returns the wrapped Object.
+	Object() tdqt.Object
+}
+
+type entity struct {
+	id  uint64
+	obj tdqt.Object
+}
+
+func (e entity) ID() uint64 {
+	return e.id
+}
+
+// Hash returns the Entity's ID.
+func (e entity) Hash() uint64 {
+	return e.id
+}
+
+func (e entity) Object() tdqt.Object {
+	return e.obj
+}
+
+func (e entity) String() string {
+	return fmt.Sprintf("#%d %v", e.id, e.obj)
+}
+
+func (e entity) Overlaps(r tdqt.Rectangle) (bool, bool) {
+	return e.obj.Overlaps(r)
+}
+
+// IntersectsSegment reports whether the wrapped Object intersects the segment,
+// if it implements tdqt.SegmentIntersector.
+func (e entity) IntersectsSegment(x1, y1, x2, y2 int64) bool {
+	si, ok := e.obj.(tdqt.SegmentIntersector)
+	return ok && si.IntersectsSegment(x1, y1, x2, y2)
+}
+
+// DrawRaster draws the wrapped Object, if it implements render.RasterDrawer.
+func (e entity) DrawRaster(dst draw.Image, v render.Viewport) {
+	if rd, ok := e.obj.(render.RasterDrawer); ok {
+		rd.DrawRaster(dst, v)
+	}
+}
+
+// DrawSVG draws the wrapped Object, if it implements render.SVGDrawer.
+func (e entity) DrawSVG(w io.Writer, v render.Viewport) error {
+	if sd, ok := e.obj.(render.SVGDrawer); ok {
+		return sd.DrawSVG(w, v)
+	}
+
+	return nil
+}
+
+// anchoredEntity wraps an Object which is tdqt.Anchored.
+type anchoredEntity struct {
+	entity
+}
+
+func (e anchoredEntity) Anchor() (int64, int64) {
+	return e.obj.(tdqt.Anchored).Anchor()
+}
+
+// boundedEntity wraps an Object which is tdqt.Bounded.
+type boundedEntity struct {
+	entity
+}
+
+func (e boundedEntity) Bounds() tdqt.Rectangle {
+	return e.obj.(tdqt.Bounded).Bounds()
+}
+
+// anchoredBoundedEntity wraps an Object which is both tdqt.Anchored and
+// tdqt.Bounded.
+type anchoredBoundedEntity struct {
+	entity
+}
+
+func (e anchoredBoundedEntity) Anchor() (int64, int64) {
+	return e.obj.(tdqt.Anchored).Anchor()
+}
+
+func (e anchoredBoundedEntity) Bounds() tdqt.Rectangle {
+	return e.obj.(tdqt.Bounded).Bounds()
+}
+
+// NewEntity returns an Entity identifying obj by id.
+func NewEntity(id uint64, obj tdqt.Object) Entity {
+	e := entity{id: id, obj: obj}
+	_, anchored := obj.(tdqt.Anchored)
+	_, bounded := obj.(tdqt.Bounded)
+
+	switch {
+	case anchored && bounded:
+		return anchoredBoundedEntity{e}
+	case anchored:
+		return anchoredEntity{e}
+	case bounded:
+		return boundedEntity{e}
+	}
+
+	return e
+}
@@ -0,0 +1,94 @@
+package objects_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/chrismarget/two-dimensional-quad-tree/objects"
+	"github.com/chrismarget/two-dimensional-quad-tree/tdqt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntity(t *testing.T) {
+	line := objects.NewColorLine(0, 0, 10, 10, color.RGBA{})
+	e := objects.NewEntity(42, line)
+
+	require.Equal(t, uint64(42), e.ID())
+	require.Equal(t, uint64(42), e.Hash())
+	require.Equal(t, tdqt.Object(line), e.Object())
+
+	for _, r := range []tdqt.Rectangle{
+		tdqt.NewRectangle(tdqt.NewLimits(0, 5), tdqt.NewLimits(0, 5)),
+		tdqt.NewRectangle(tdqt.NewLimits(0, 5), tdqt.NewLimits(6, 10)),
+		tdqt.NewRectangle(tdqt.NewLimits(-1, 11), tdqt.NewLimits(-1, 11)),
+	} {
+		o, c := line.Overlaps(r)
+		eo, ec := e.Overlaps(r)
+		require.Equal(t, o, eo)
+		require.Equal(t, c, ec)
+	}
+
+	require.True(t, e.IntersectsSegment(0, 10, 10, 0))
+	require.False(t, e.IntersectsSegment(0, 1, 9, 10))
+	require.False(t, objects.NewEntity(42, plainObject{}).IntersectsSegment(0, 0, 10, 10))
+}
+
+// plainObject is an Object which is neither Anchored nor Bounded.
+type plainObject struct{}
+
+func (plainObject) Hash() uint64                         { return 0 }
+func (plainObject) Overlaps(tdqt.Rectangle) (bool, bool) { return false, false }
+
+// anchoredObject is an Object which is Anchored but not Bounded.
+type anchoredObject struct {
+	plainObject
+}
+
+func (anchoredObject) Anchor() (int64, int64) { return 3, 4 }
+
+// boundedObject is an Object which is Bounded but not Anchored.
+type boundedObject struct {
+	plainObject
+}
+
+func (boundedObject) Bounds() tdqt.Rectangle {
+	return tdqt.NewRectangle(tdqt.NewLimits(1, 2), tdqt.NewLimits(3, 4))
+}
+
+func TestNewEntity_Forwarding(t *testing.T) {
+	type testCase struct {
+		obj      tdqt.Object
+		anchored bool
+		bounded  bool
+	}
+
+	testCases := map[string]testCase{
+		"plain":    {obj: plainObject{}},
+		"anchored": {obj: anchoredObject{}, anchored: true},
+		"bounded":  {obj: boundedObject{}, bounded: true},
+		"both":     {obj: objects.NewColorPoint(5, 6, color.RGBA{}), anchored: true, bounded: true},
+	}
+
+	for tName, tCase := range testCases {
+		t.Run(tName, func(t *testing.T) {
+			t.Parallel()
+
+			e := objects.NewEntity(7, tCase.obj)
+			require.Equal(t, tCase.obj, e.Object())
+
+			a, ok := e.(tdqt.Anchored)
+			require.Equal(t, tCase.anchored, ok)
+			if ok {
+				x, y := a.Anchor()
+				wx, wy := tCase.obj.(tdqt.Anchored).Anchor()
+				require.Equal(t, [2]int64{wx, wy}, [2]int64{x, y})
+			}
+
+			b, ok := e.(tdqt.Bounded)
+			require.Equal(t, tCase.bounded, ok)
+			if ok {
+				require.Equal(t, tCase.obj.(tdqt.Bounded).Bounds().String(), b.Bounds().String())
+			}
+		})
+	}
+}
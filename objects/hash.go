@@ -6,6 +6,13 @@
 
 package objects
 
+import (
+	"encoding/binary"
+	"hash/maphash"
+
+	"github.com/chrismarget/two-dimensional-quad-tree/tdqt"
+)
+
 const (
 	fnvBasis = 14695981039346656037
 	FnvPrime = 1099511628211
@@ -22,3 +29,35 @@ func FnvHash(s []byte) (h uint64) {
 	}
 	return
 }
+
+// hashInputAppender is implemented by the objects in this package. The bytes
+// it appends identify the object, and are what its Hash is computed from.
+type hashInputAppender interface {
+	appendHashInput(b []byte, hash tdqt.HashFunc) []byte
+}
+
+// HashWith returns a tdqt.HashFunc which hashes the objects in this package
+// using f rather than FNV. Pass it to tdqt.WithHashFunc. Any func([]byte)
+// uint64 will do, such as xxhash.Sum64 or the result of SeededHash. Objects
+// from other packages are keyed by f applied to their own Hash.
+func HashWith(f func([]byte) uint64) tdqt.HashFunc {
+	var hash tdqt.HashFunc
+	hash = func(obj tdqt.Object) uint64 {
+		if a, ok := obj.(hashInputAppender); ok {
+			return f(a.appendHashInput(nil, hash))
+		}
+		return f(binary.BigEndian.AppendUint64(nil, obj.Hash()))
+	}
+
+	return hash
+}
+
+// SeededHash returns a hash function keyed with a random seed, suitable for
+// use with HashWith. Without knowing the seed, adversarial inputs can't be
+// crafted to collide.
+func SeededHash() func([]byte) uint64 {
+	seed := maphash.MakeSeed()
+	return func(b []byte) uint64 {
+		return maphash.Bytes(seed, b)
+	}
+}
@@ -0,0 +1,83 @@
+package objects_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/chrismarget/two-dimensional-quad-tree/objects"
+	"github.com/chrismarget/two-dimensional-quad-tree/tdqt"
+	"github.com/stretchr/testify/require"
+)
+
+// sampleObjects returns one of each object in this package, along with an
+// object which differs from it only slightly.
+func sampleObjects() map[string][2]tdqt.Object {
+	red := color.RGBA{R: 255, A: 255}
+	square := []objects.Vertex{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}}
+	rect := func(xMax int64) tdqt.Rectangle {
+		return tdqt.NewRectangle(tdqt.NewLimits(0, xMax), tdqt.NewLimits(0, 10))
+	}
+
+	return map[string][2]tdqt.Object{
+		"point":    {objects.NewColorPoint(1, 2, red), objects.NewColorPoint(1, 2, color.RGBA{})},
+		"line":     {objects.NewColorLine(1, 2, 3, 4, red), objects.NewColorLine(1, 2, 3, 5, red)},
+		"rect":     {objects.NewColorRect(rect(10), red), objects.NewColorRect(rect(11), red)},
+		"polygon":  {objects.NewColorPolygon(square, nil, red), objects.NewColorPolygon(square[1:], nil, red)},
+		"polyline": {objects.NewColorPolyline(square, red), objects.NewColorPolyline(square[:3], red)},
+		"circle":   {objects.NewColorCircle(1, 2, 3, red), objects.NewColorEllipse(1, 2, 3, 4, red)},
+		"label":    {objects.NewLabel(1, 2, "x", 10, red), objects.NewLabel(1, 2, "y", 10, red)},
+		"composite": {
+			objects.NewComposite(objects.NewColorPoint(1, 2, red), objects.NewColorLine(1, 2, 3, 4, red)),
+			objects.NewComposite(objects.NewColorPoint(1, 2, red)),
+		},
+	}
+}
+
+func TestHashWith(t *testing.T) {
+	fnv := objects.HashWith(objects.FnvHash)
+	seeded := objects.HashWith(objects.SeededHash())
+	otherSeed := objects.HashWith(objects.SeededHash())
+
+	for name, pair := range sampleObjects() {
+		t.Run(name, func(t *testing.T) {
+			obj, other := pair[0], pair[1]
+
+			// FNV reproduces the objects' own hashes
+			require.Equal(t, obj.Hash(), fnv(obj))
+
+			require.Equal(t, seeded(obj), seeded(obj))
+			require.NotEqual(t, seeded(obj), seeded(other))
+			require.NotEqual(t, seeded(obj), otherSeed(obj))
+		})
+	}
+}
+
+func TestEqual(t *testing.T) {
+	samples := sampleObjects()
+	for name, pair := range samples {
+		t.Run(name, func(t *testing.T) {
+			obj, other := pair[0].(tdqt.Equaler), pair[1]
+
+			require.True(t, obj.Equal(pair[0]))
+			require.False(t, obj.Equal(other))
+
+			for otherName, otherPair := range samples {
+				if otherName != name {
+					require.False(t, obj.Equal(otherPair[0]))
+				}
+			}
+		})
+	}
+}
+
+func TestHashWith_Tree(t *testing.T) {
+	everywhere := tdqt.NewRectangle(tdqt.NewLimits(0, 100), tdqt.NewLimits(0, 100))
+	tree := tdqt.NewTree(everywhere, tdqt.WithHashFunc(objects.HashWith(objects.SeededHash())))
+
+	for _, pair := range sampleObjects() {
+		tree.Insert(pair[0])
+		tree.Insert(pair[1])
+	}
+
+	require.Len(t, tree.SearchAll(everywhere), 16)
+}
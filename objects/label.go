@@ -0,0 +1,217 @@
+package objects
+
+import (
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"image/color"
+	"image/draw"
+	"io"
+	"math"
+	"math/bits"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/chrismarget/two-dimensional-quad-tree/internal/exact"
+	"github.com/chrismarget/two-dimensional-quad-tree/render"
+	"github.com/chrismarget/two-dimensional-quad-tree/tdqt"
+)
+
+var (
+	_ tdqt.Object             = (*Label)(nil)
+	_ tdqt.Anchored           = (*Label)(nil)
+	_ tdqt.Equaler            = (*Label)(nil)
+	_ tdqt.Bounded            = (*Label)(nil)
+	_ tdqt.SegmentIntersector = (*Label)(nil)
+	_ render.RasterDrawer     = (*Label)(nil)
+	_ render.SVGDrawer        = (*Label)(nil)
+)
+
+// Each character of a Label advances labelAdvanceNum/labelAdvanceDen of the
+// font size, which approximates a typical monospaced font.
+const (
+	labelAdvanceNum = 3
+	labelAdvanceDen = 5
+)
+
+// Label is a text annotation with a color. Its anchor is the bottom left
+// corner of the text. The label occupies a box extending up from the anchor
+// by the font size, and right by the font size scaled by the advance width of
+// each character. All coordinates and sizes are in world (tree) units.
+type Label struct {
+	x, y     int64
+	text     string
+	fontSize uint64
+	bounds   tdqt.Rectangle
+	color    color.RGBA
+	hash     uint64
+}
+
+func (l Label) Anchor() (int64, int64) {
+	return l.x, l.y
+}
+
+func (l Label) Bounds() tdqt.Rectangle {
+	return l.bounds
+}
+
+func (l Label) Hash() uint64 {
+	return l.hash
+}
+
+func (l Label) Text() string {
+	return l.text
+}
+
+func (l Label) Color() color.RGBA {
+	return l.color
+}
+
+func (l Label) String() string {
+	return fmt.Sprintf("(%d,%d) %q@%d: (%d,%d,%d,%d)", l.x, l.y, l.text, l.fontSize, l.color.R, l.color.G, l.color.B, l.color.A)
+}
+
+func (l Label) Equal(o tdqt.Object) bool {
+	other, ok := o.(Label)
+	return ok && l.x == other.x && l.y == other.y && l.text == other.text && l.fontSize == other.fontSize && l.color == other.color
+}
+
+func (l *Label) computeHash() {
+	l.hash = FnvHash(l.appendHashInput(nil, tdqt.Object.Hash))
+}
+
+// appendHashInput appends the bytes which identify the Label to b.
+func (l Label) appendHashInput(b []byte, _ tdqt.HashFunc) []byte {
+	bytes := slices.Grow(b, 28+len(l.text))
+	bytes = binary.BigEndian.AppendUint64(bytes, uint64(l.x))
+	bytes = binary.BigEndian.AppendUint64(bytes, uint64(l.y))
+	bytes = binary.BigEndian.AppendUint64(bytes, l.fontSize)
+	bytes = append(bytes, l.color.R, l.color.G, l.color.B, l.color.A)
+	bytes = append(bytes, l.text...)
+
+	return bytes
+}
+
+// Overlaps compares the label's bounding box with r.
+func (l Label) Overlaps(r tdqt.Rectangle) (bool, bool) {
+	if !l.bounds.Overlaps(r) {
+		return false, false
+	}
+
+	return true, r.ContainsRect(l.bounds)
+}
+
+// IntersectsSegment indicates whether the segment crosses the Label's
+// bounding box.
+func (l Label) IntersectsSegment(x1, y1, x2, y2 int64) bool {
+	overlap, _ := lineOverlaps(x1, y1, x2, y2, l.bounds)
+	return overlap
+}
+
+// DrawRaster outlines the label's extent. Rendering glyphs requires fonts,
+// which are beyond the scope of this package.
+func (l Label) DrawRaster(dst draw.Image, v render.Viewport) {
+	xLimits, yLimits := l.bounds.Limits()
+	x0, y0 := v.Point(xLimits.Min(), yLimits.Min())
+	x1, y1 := v.Point(xLimits.Max(), yLimits.Max())
+	render.Line(dst, x0, y0, x1, y0, l.color)
+	render.Line(dst, x1, y0, x1, y1, l.color)
+	render.Line(dst, x1, y1, x0, y1, l.color)
+	render.Line(dst, x0, y1, x0, y0, l.color)
+}
+
+func (l Label) DrawSVG(w io.Writer, v render.Viewport) error {
+	x, y := v.Point(l.x, l.y)
+	fill, opacity := render.SVGColor(l.color)
+
+	var text strings.Builder
+	if err := xml.EscapeText(&text, []byte(l.text)); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, `<text x="%g" y="%g" font-family="monospace" font-size="%g" fill="%s" fill-opacity="%s">%s</text>`+"\n",
+		x, y, v.DY(l.fontSize), fill, opacity, text.String())
+	return err
+}
+
+// NewLabelE returns a Label anchored at (x,y). An error is returned if text is
+// empty, fontSize is zero, or the label's extent doesn't fit in the int64
+// coordinate plane.
+func NewLabelE(x, y int64, text string, fontSize uint64, color color.RGBA) (Label, error) {
+	if text == "" {
+		return Label{}, errors.New("label text must not be empty")
+	}
+
+	if fontSize == 0 {
+		return Label{}, errors.New("label font size must be greater than zero")
+	}
+
+	// width = runes * fontSize * num / den, computed without overflow
+	hi, lo := bits.Mul64(uint64(utf8.RuneCountInString(text)), fontSize)
+	if hi >= labelAdvanceDen {
+		return Label{}, fmt.Errorf("label %q is too wide", text)
+	}
+	q, rem := bits.Div64(hi, lo, labelAdvanceDen)
+	if q > math.MaxInt64/labelAdvanceNum {
+		return Label{}, fmt.Errorf("label %q is too wide", text)
+	}
+	width := max(1, q*labelAdvanceNum+rem*labelAdvanceNum/labelAdvanceDen)
+
+	_, room := exact.Difference(math.MaxInt64, x)
+	if width > room {
+		return Label{}, fmt.Errorf("label %q at x=%d is too wide", text, x)
+	}
+
+	_, room = exact.Difference(math.MaxInt64, y)
+	if fontSize > room {
+		return Label{}, fmt.Errorf("label %q at y=%d is too tall", text, y)
+	}
+
+	result := Label{
+		x:        x,
+		y:        y,
+		text:     text,
+		fontSize: fontSize,
+		bounds: tdqt.NewRectangle(
+			tdqt.NewLimits(x, int64(uint64(x)+width)),
+			tdqt.NewLimits(y, int64(uint64(y)+fontSize)),
+		),
+		color: color,
+	}
+
+	result.computeHash()
+
+	return result, nil
+}
+
+// NewLabel is a convenience wrapper around NewLabelE which panics rather than
+// returning an error.
+func NewLabel(x, y int64, text string, fontSize uint64, color color.RGBA) Label {
+	result, err := NewLabelE(x, y, text, fontSize, color)
+	if err != nil {
+		panic(err)
+	}
+
+	return result
+}
+
+// PlaceLabel inserts l into t, unless it would overlap a Label already in t.
+// It reports whether l was placed. Other kinds of objects don't prevent
+// placement. Calling PlaceLabel with labels in priority order produces a
+// greedy, collision-free labeling.
+//
+// The search and the insert are separate Tree operations, so concurrent
+// callers placing labels into the same Tree must serialize their calls.
+func PlaceLabel(t *tdqt.Tree, l Label) bool {
+	// SearchAll, because a Label may share its hash with another object.
+	for _, obj := range t.SearchAll(l.bounds) {
+		if _, ok := obj.(Label); ok {
+			return false
+		}
+	}
+
+	t.Insert(l)
+	return true
+}
@@ -0,0 +1,130 @@
+package objects_test
+
+import (
+	"bytes"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/chrismarget/two-dimensional-quad-tree/objects"
+	"github.com/chrismarget/two-dimensional-quad-tree/render"
+	"github.com/chrismarget/two-dimensional-quad-tree/tdqt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabel_Bounds(t *testing.T) {
+	type testCase struct {
+		text       string
+		fontSize   uint64
+		xMax, yMax int64
+	}
+
+	testCases := map[string]testCase{
+		"short":     {text: "hi", fontSize: 10, xMax: 12, yMax: 10},
+		"rounding":  {text: "abc", fontSize: 3, xMax: 5, yMax: 3},
+		"multibyte": {text: "héllo", fontSize: 10, xMax: 30, yMax: 10},
+		"tiny":      {text: "x", fontSize: 1, xMax: 1, yMax: 1},
+	}
+
+	for tName, tCase := range testCases {
+		t.Run(tName, func(t *testing.T) {
+			t.Parallel()
+
+			l := objects.NewLabel(0, 0, tCase.text, tCase.fontSize, color.RGBA{})
+			expected := tdqt.NewRectangle(tdqt.NewLimits(0, tCase.xMax), tdqt.NewLimits(0, tCase.yMax))
+			require.Equal(t, expected.String(), l.Bounds().String())
+		})
+	}
+}
+
+func TestLabel_Overlaps(t *testing.T) {
+	l := objects.NewLabel(10, 10, "hello", 10, color.RGBA{}) // [10,40) x [10,20)
+
+	type testCase struct {
+		xMin, xMax, yMin, yMax int64
+		overlap                bool
+		fullyContained         bool
+	}
+
+	testCases := map[string]testCase{
+		"contains":      {xMin: 0, xMax: 50, yMin: 0, yMax: 50, overlap: true, fullyContained: true},
+		"exact":         {xMin: 10, xMax: 40, yMin: 10, yMax: 20, overlap: true, fullyContained: true},
+		"crosses":       {xMin: 35, xMax: 50, yMin: 0, yMax: 50, overlap: true},
+		"abuts_right":   {xMin: 40, xMax: 50, yMin: 0, yMax: 50},
+		"abuts_top":     {xMin: 0, xMax: 50, yMin: 20, yMax: 50},
+		"abuts_left":    {xMin: 0, xMax: 10, yMin: 0, yMax: 50},
+		"inside_extent": {xMin: 20, xMax: 22, yMin: 12, yMax: 14, overlap: true},
+	}
+
+	for tName, tCase := range testCases {
+		t.Run(tName, func(t *testing.T) {
+			t.Parallel()
+
+			r := tdqt.NewRectangle(tdqt.NewLimits(tCase.xMin, tCase.xMax), tdqt.NewLimits(tCase.yMin, tCase.yMax))
+			overlap, fullyContained := l.Overlaps(r)
+			require.Equalf(t, tCase.overlap, overlap, "label %s should overlap rectangle %s", l.String(), r.String())
+			require.Equalf(t, tCase.fullyContained, fullyContained, "label %s should be fully contained by rectangle %s", l.String(), r.String())
+		})
+	}
+}
+
+func TestNewLabelE(t *testing.T) {
+	_, err := objects.NewLabelE(0, 0, "", 10, color.RGBA{})
+	require.Error(t, err)
+
+	_, err = objects.NewLabelE(0, 0, "x", 0, color.RGBA{})
+	require.Error(t, err)
+
+	_, err = objects.NewLabelE(math.MaxInt64-5, 0, "hello", 10, color.RGBA{})
+	require.Error(t, err)
+
+	_, err = objects.NewLabelE(0, math.MaxInt64-5, "hello", 10, color.RGBA{})
+	require.Error(t, err)
+
+	_, err = objects.NewLabelE(0, 0, "hello", math.MaxUint64, color.RGBA{})
+	require.Error(t, err)
+
+	require.NotEqual(t, objects.NewLabel(0, 0, "ab", 10, color.RGBA{}).Hash(), objects.NewLabel(0, 0, "ba", 10, color.RGBA{}).Hash())
+}
+
+func TestPlaceLabel(t *testing.T) {
+	tree := tdqt.NewTree(tdqt.NewRectangle(tdqt.NewLimits(0, 1000), tdqt.NewLimits(0, 1000)), tdqt.WithMaxObjects(2))
+
+	// non-label objects don't block placement
+	tree.Insert(objects.NewColorRect(tdqt.NewRectangle(tdqt.NewLimits(0, 1000), tdqt.NewLimits(0, 1000)), color.RGBA{}))
+
+	require.True(t, objects.PlaceLabel(tree, objects.NewLabel(100, 100, "Springfield", 20, color.RGBA{}))) // [100,232) x [100,120)
+	require.False(t, objects.PlaceLabel(tree, objects.NewLabel(200, 110, "Shelbyville", 20, color.RGBA{})))
+	require.True(t, objects.PlaceLabel(tree, objects.NewLabel(232, 100, "Ogdenville", 20, color.RGBA{})))   // abuts on the right
+	require.True(t, objects.PlaceLabel(tree, objects.NewLabel(100, 120, "Capital City", 20, color.RGBA{}))) // abuts above
+	require.False(t, objects.PlaceLabel(tree, objects.NewLabel(90, 90, "North Haverbrook", 20, color.RGBA{})))
+
+	var labels int
+	for _, obj := range tree.Search(tdqt.NewRectangle(tdqt.NewLimits(0, 1000), tdqt.NewLimits(0, 1000))) {
+		if _, ok := obj.(objects.Label); ok {
+			labels++
+		}
+	}
+	require.Equal(t, 3, labels)
+}
+
+func TestPlaceLabel_HashCollision(t *testing.T) {
+	// Every object shares one hash, so Search would return only one of them.
+	tree := tdqt.NewTree(tdqt.NewRectangle(tdqt.NewLimits(0, 1000), tdqt.NewLimits(0, 1000)),
+		tdqt.WithHashFunc(func(tdqt.Object) uint64 { return 0 }))
+
+	require.True(t, objects.PlaceLabel(tree, objects.NewLabel(100, 100, "Springfield", 20, color.RGBA{})))
+	tree.Insert(objects.NewColorPoint(210, 115, color.RGBA{})) // where both labels would be
+	require.Len(t, tree.Search(tdqt.NewRectangle(tdqt.NewLimits(0, 1000), tdqt.NewLimits(0, 1000))), 1)
+
+	require.False(t, objects.PlaceLabel(tree, objects.NewLabel(200, 110, "Shelbyville", 20, color.RGBA{})))
+}
+
+func TestLabel_DrawSVG(t *testing.T) {
+	area := tdqt.NewRectangle(tdqt.NewLimits(0, 100), tdqt.NewLimits(0, 100))
+	l := objects.NewLabel(10, 10, "A&B", 10, color.RGBA{B: 255, A: 255})
+
+	var buf bytes.Buffer
+	require.NoError(t, l.DrawSVG(&buf, render.NewViewport(area, 200, 200)))
+	require.Equal(t, `<text x="20" y="180" font-family="monospace" font-size="20" fill="rgb(0,0,255)" fill-opacity="1.000">A&amp;B</text>`+"\n", buf.String())
+}
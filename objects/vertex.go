@@ -0,0 +1,26 @@
+package objects
+
+import "fmt"
+
+// Vertex is an (x,y) coordinate pair used to describe the corners of
+// multi-segment objects.
+type Vertex struct {
+	X int64
+	Y int64
+}
+
+func (v Vertex) String() string {
+	return fmt.Sprintf("(%d,%d)", v.X, v.Y)
+}
+
+// verticesExtent returns the inclusive minimum and maximum x and y
+// coordinates of vs, which must not be empty.
+func verticesExtent(vs []Vertex) (int64, int64, int64, int64) {
+	xMin, xMax, yMin, yMax := vs[0].X, vs[0].X, vs[0].Y, vs[0].Y
+	for _, v := range vs[1:] {
+		xMin, xMax = min(xMin, v.X), max(xMax, v.X)
+		yMin, yMax = min(yMin, v.Y), max(yMax, v.Y)
+	}
+
+	return xMin, xMax, yMin, yMax
+}
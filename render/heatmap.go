@@ -0,0 +1,77 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+)
+
+// Ramp maps a value between 0 and 1 to a color.
+type Ramp func(v float64) color.RGBA
+
+// HeatRamp is a Ramp which runs from black through red and yellow to white.
+// Values outside of 0-1 are clamped.
+func HeatRamp(v float64) color.RGBA {
+	s := 3 * min(max(v, 0), 1)
+	channel := func(c float64) uint8 {
+		return uint8(math.Round(255 * min(max(c, 0), 1)))
+	}
+
+	return color.RGBA{R: channel(s), G: channel(s - 1), B: channel(s - 2), A: 255}
+}
+
+// Heatmap draws grid, a table of counts indexed [row][col] such as the result
+// of Tree.Density, as a new image. The grid is spread over v.Area as by
+// Rectangle.Cells, so row 0 appears at the bottom of the image. Each cell is
+// colored by ramp according to its count relative to the largest in grid.
+// A nil ramp means HeatRamp. Rows shorter than the longest are padded with
+// zeros.
+func Heatmap(v Viewport, grid [][]uint64, ramp Ramp) *image.RGBA {
+	if ramp == nil {
+		ramp = HeatRamp
+	}
+
+	img := image.NewRGBA(v.bounds())
+	draw.Draw(img, img.Bounds(), image.NewUniform(ramp(0)), image.Point{}, draw.Src)
+
+	var cols int
+	var most uint64
+	for _, row := range grid {
+		cols = max(cols, len(row))
+		for _, n := range row {
+			most = max(most, n)
+		}
+	}
+	if most == 0 {
+		return img
+	}
+
+	cells := v.Area.Cells(cols, len(grid))
+	for i, row := range grid {
+		for j, n := range row {
+			if n == 0 {
+				continue // already painted
+			}
+
+			// Round each cell's edges to the nearest pixel boundary, so that
+			// neighboring cells neither overlap nor leave gaps.
+			xLimits, yLimits := cells[i][j].Limits()
+			r := image.Rect(
+				int(math.Round(v.X(xLimits.Min()))), int(math.Round(v.Y(yLimits.Max()))),
+				int(math.Round(v.X(xLimits.Max()))), int(math.Round(v.Y(yLimits.Min()))),
+			)
+			c := ramp(float64(n) / float64(most))
+			draw.Draw(img, r.Intersect(img.Bounds()), image.NewUniform(c), image.Point{}, draw.Src)
+		}
+	}
+
+	return img
+}
+
+// WriteHeatmapPNG writes the image drawn by Heatmap to w as a PNG.
+func WriteHeatmapPNG(w io.Writer, v Viewport, grid [][]uint64, ramp Ramp) error {
+	return png.Encode(w, Heatmap(v, grid, ramp))
+}
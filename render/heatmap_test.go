@@ -0,0 +1,67 @@
+package render_test
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/chrismarget/two-dimensional-quad-tree/objects"
+	"github.com/chrismarget/two-dimensional-quad-tree/render"
+	"github.com/chrismarget/two-dimensional-quad-tree/tdqt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeatRamp(t *testing.T) {
+	require.Equal(t, color.RGBA{A: 255}, render.HeatRamp(0))
+	require.Equal(t, color.RGBA{A: 255}, render.HeatRamp(-1))
+	require.Equal(t, color.RGBA{R: 255, A: 255}, render.HeatRamp(1.0/3))
+	require.Equal(t, color.RGBA{R: 255, G: 255, A: 255}, render.HeatRamp(2.0/3))
+	require.Equal(t, color.RGBA{R: 255, G: 255, B: 255, A: 255}, render.HeatRamp(1))
+	require.Equal(t, color.RGBA{R: 255, G: 255, B: 255, A: 255}, render.HeatRamp(2))
+}
+
+func TestHeatmap(t *testing.T) {
+	area := tdqt.NewRectangle(tdqt.NewLimits(0, 100), tdqt.NewLimits(0, 100))
+	tree := tdqt.NewTree(area, tdqt.WithMaxObjects(2))
+
+	red := color.RGBA{R: 255, A: 255}
+	tree.Insert(objects.NewColorPoint(10, 10, red)) // bottom left
+	for i := range int64(4) {
+		tree.Insert(objects.NewColorPoint(60+i, 60+i, red)) // top right
+	}
+
+	grid := tree.Density(area, 2, 2)
+	require.Equal(t, [][]uint64{{1, 0}, {0, 4}}, grid)
+
+	// 10 world units per pixel
+	v := render.NewViewport(area, 10, 10)
+	img := render.Heatmap(v, grid, nil)
+	require.Equal(t, render.HeatRamp(0.25), img.RGBAAt(0, 9))
+	require.Equal(t, render.HeatRamp(0.25), img.RGBAAt(4, 5))
+	require.Equal(t, render.HeatRamp(1), img.RGBAAt(5, 4))
+	require.Equal(t, render.HeatRamp(1), img.RGBAAt(9, 0))
+	require.Equal(t, render.HeatRamp(0), img.RGBAAt(0, 0))
+	require.Equal(t, render.HeatRamp(0), img.RGBAAt(9, 9))
+
+	gray := func(v float64) color.RGBA {
+		g := uint8(v * 200)
+		return color.RGBA{R: g, G: g, B: g, A: 255}
+	}
+	img = render.Heatmap(v, grid, gray)
+	require.Equal(t, gray(1), img.RGBAAt(9, 0))
+	require.Equal(t, gray(0), img.RGBAAt(0, 0))
+
+	var buf bytes.Buffer
+	require.NoError(t, render.WriteHeatmapPNG(&buf, v, grid, nil))
+	decoded, err := png.Decode(&buf)
+	require.NoError(t, err)
+	require.Equal(t, img.Bounds(), decoded.Bounds())
+	img = render.Heatmap(v, grid, nil)
+	for x := range 10 {
+		for y := range 10 {
+			r, g, b, a := decoded.At(x, y).RGBA()
+			require.Equal(t, img.RGBAAt(x, y), color.RGBA{R: uint8(r >> 8), G: uint8(g >> 8), B: uint8(b >> 8), A: uint8(a >> 8)})
+		}
+	}
+}
@@ -0,0 +1,314 @@
+// Package render draws tdqt Objects as raster images or SVG documents.
+//
+// Objects opt in by implementing RasterDrawer and/or SVGDrawer. Objects which
+// implement neither are skipped.
+package render
+
+import (
+	"cmp"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/chrismarget/two-dimensional-quad-tree/tdqt"
+)
+
+// RasterDrawer is implemented by objects which can draw themselves onto a
+// raster image.
+type RasterDrawer interface {
+	DrawRaster(dst draw.Image, v Viewport)
+}
+
+// SVGDrawer is implemented by objects which can describe themselves as SVG
+// elements.
+type SVGDrawer interface {
+	DrawSVG(w io.Writer, v Viewport) error
+}
+
+// Viewport maps Area, a region of the tree's coordinate plane, onto an image
+// Width pixels wide and Height pixels tall. The coordinate plane's y-axis
+// points up, while the image's points down.
+type Viewport struct {
+	Area   tdqt.Rectangle
+	Width  int
+	Height int
+}
+
+// NewViewport returns a Viewport which maps area onto an image of the given
+// dimensions.
+func NewViewport(area tdqt.Rectangle, width, height int) Viewport {
+	return Viewport{Area: area, Width: width, Height: height}
+}
+
+// X converts an x coordinate to a (fractional) pixel column.
+func (v Viewport) X(x int64) float64 {
+	xLimits, _ := v.Area.Limits()
+	return offset(xLimits.Min(), x) * v.scaleX()
+}
+
+// Y converts a y coordinate to a (fractional) pixel row.
+func (v Viewport) Y(y int64) float64 {
+	_, yLimits := v.Area.Limits()
+	return offset(y, yLimits.Max()) * v.scaleY()
+}
+
+// Point converts an (x,y) coordinate pair to a (fractional) pixel position.
+func (v Viewport) Point(x, y int64) (float64, float64) {
+	return v.X(x), v.Y(y)
+}
+
+// DX converts a distance along the x-axis to pixels.
+func (v Viewport) DX(d uint64) float64 {
+	return float64(d) * v.scaleX()
+}
+
+// DY converts a distance along the y-axis to pixels.
+func (v Viewport) DY(d uint64) float64 {
+	return float64(d) * v.scaleY()
+}
+
+func (v Viewport) scaleX() float64 {
+	xLimits, _ := v.Area.Limits()
+	return float64(v.Width) / offset(xLimits.Min(), xLimits.Max())
+}
+
+func (v Viewport) scaleY() float64 {
+	_, yLimits := v.Area.Limits()
+	return float64(v.Height) / offset(yLimits.Min(), yLimits.Max())
+}
+
+func (v Viewport) bounds() image.Rectangle {
+	return image.Rect(0, 0, v.Width, v.Height)
+}
+
+// offset returns b-a as a float64, without overflowing when a and b are far
+// apart.
+func offset(a, b int64) float64 {
+	if b >= a {
+		return float64(uint64(b) - uint64(a))
+	}
+	return -float64(uint64(a) - uint64(b))
+}
+
+// sorted returns the objects in a deterministic (hash) order, so that output
+// doesn't depend on map iteration order.
+func sorted(objs map[uint64]tdqt.Object) []tdqt.Object {
+	keys := make([]uint64, 0, len(objs))
+	for k := range objs {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, cmp.Compare)
+
+	result := make([]tdqt.Object, len(keys))
+	for i, k := range keys {
+		result[i] = objs[k]
+	}
+	return result
+}
+
+// Raster draws objs (typically the result of Tree.Search) onto a new image
+// filled with background.
+func Raster(v Viewport, objs map[uint64]tdqt.Object, background color.Color) *image.RGBA {
+	img := image.NewRGBA(v.bounds())
+	draw.Draw(img, img.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+
+	for _, obj := range sorted(objs) {
+		if rd, ok := obj.(RasterDrawer); ok {
+			rd.DrawRaster(img, v)
+		}
+	}
+
+	return img
+}
+
+// SVG writes an SVG document depicting objs (typically the result of
+// Tree.Search) to w.
+func SVG(w io.Writer, v Viewport, objs map[uint64]tdqt.Object) error {
+	_, err := fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		v.Width, v.Height, v.Width, v.Height)
+	if err != nil {
+		return err
+	}
+
+	for _, obj := range sorted(objs) {
+		if sd, ok := obj.(SVGDrawer); ok {
+			if err = sd.DrawSVG(w, v); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err = fmt.Fprintln(w, "</svg>")
+	return err
+}
+
+// FillRect fills the pixels covering the fractional pixel rectangle
+// (x0,y0)-(x1,y1) with c. At least one pixel is always filled, so that tiny
+// objects remain visible.
+func FillRect(dst draw.Image, x0, y0, x1, y1 float64, c color.Color) {
+	r := image.Rect(
+		int(math.Floor(min(x0, x1))), int(math.Floor(min(y0, y1))),
+		int(math.Ceil(max(x0, x1))), int(math.Ceil(max(y0, y1))),
+	)
+	if r.Dx() == 0 {
+		r.Max.X++
+	}
+	if r.Dy() == 0 {
+		r.Max.Y++
+	}
+
+	draw.Draw(dst, r.Intersect(dst.Bounds()), image.NewUniform(c), image.Point{}, draw.Over)
+}
+
+// Line draws a one pixel wide line between fractional pixel positions
+// (x0,y0) and (x1,y1).
+func Line(dst draw.Image, x0, y0, x1, y1 float64, c color.Color) {
+	b := dst.Bounds()
+
+	// Trim the line to (just beyond) the image, so that lines which run far
+	// off the edge don't take forever to draw.
+	lo := float64(min(b.Min.X, b.Min.Y) - 1)
+	hi := float64(max(b.Max.X, b.Max.Y) + 1)
+	x0, y0, x1, y1, ok := clipLine(x0, y0, x1, y1, lo, hi)
+	if !ok {
+		return
+	}
+
+	steps := int(math.Ceil(max(math.Abs(x1-x0), math.Abs(y1-y0))))
+	src := image.NewUniform(c)
+	for i := 0; i <= steps; i++ {
+		t := 0.0
+		if steps > 0 {
+			t = float64(i) / float64(steps)
+		}
+		p := image.Pt(int(math.Floor(x0+t*(x1-x0))), int(math.Floor(y0+t*(y1-y0))))
+		if p.In(b) {
+			draw.Draw(dst, image.Rectangle{Min: p, Max: p.Add(image.Pt(1, 1))}, src, image.Point{}, draw.Over)
+		}
+	}
+}
+
+// clipLine trims the line (x0,y0)-(x1,y1) to the square [lo,hi] on both axes
+// (Liang-Barsky). The final return value is false if the line misses the
+// square entirely.
+func clipLine(x0, y0, x1, y1, lo, hi float64) (float64, float64, float64, float64, bool) {
+	dx, dy := x1-x0, y1-y0
+	t0, t1 := 0.0, 1.0
+	for _, pq := range [4][2]float64{{-dx, x0 - lo}, {dx, hi - x0}, {-dy, y0 - lo}, {dy, hi - y0}} {
+		p, q := pq[0], pq[1]
+		switch {
+		case p == 0 && q < 0:
+			return 0, 0, 0, 0, false
+		case p < 0:
+			t0 = max(t0, q/p)
+		case p > 0:
+			t1 = min(t1, q/p)
+		}
+	}
+	if t0 > t1 {
+		return 0, 0, 0, 0, false
+	}
+	return x0 + t0*dx, y0 + t0*dy, x0 + t1*dx, y0 + t1*dy, true
+}
+
+// FillPolygon fills the pixels whose centers fall within the polygon described
+// by rings of fractional pixel positions, using the even-odd rule. Rings are
+// implicitly closed.
+func FillPolygon(dst draw.Image, rings [][][2]float64, c color.Color) {
+	b := dst.Bounds()
+
+	yMin, yMax := math.Inf(1), math.Inf(-1)
+	for _, ring := range rings {
+		for _, p := range ring {
+			yMin, yMax = min(yMin, p[1]), max(yMax, p[1])
+		}
+	}
+
+	src := image.NewUniform(c)
+	var crossings []float64
+	for row := max(b.Min.Y, int(math.Floor(yMin))); row < min(b.Max.Y, int(math.Ceil(yMax))); row++ {
+		yc := float64(row) + 0.5
+
+		crossings = crossings[:0]
+		for _, ring := range rings {
+			for i := range ring {
+				p, q := ring[i], ring[(i+1)%len(ring)]
+				if (p[1] <= yc) == (q[1] <= yc) {
+					continue
+				}
+				crossings = append(crossings, p[0]+(yc-p[1])*(q[0]-p[0])/(q[1]-p[1]))
+			}
+		}
+		slices.Sort(crossings)
+
+		for i := 0; i+1 < len(crossings); i += 2 {
+			x0 := max(b.Min.X, int(math.Ceil(crossings[i]-0.5)))
+			x1 := min(b.Max.X, int(math.Ceil(crossings[i+1]-0.5)))
+			if x0 < x1 {
+				draw.Draw(dst, image.Rect(x0, row, x1, row+1), src, image.Point{}, draw.Over)
+			}
+		}
+	}
+}
+
+// FillEllipse fills the pixels whose centers fall within the axis-aligned
+// ellipse centered at fractional pixel position (cx,cy) with radii rx and ry.
+// Ellipses too small to cover any pixel center are drawn as their bounding
+// box, so that tiny objects remain visible.
+func FillEllipse(dst draw.Image, cx, cy, rx, ry float64, c color.Color) {
+	if rx < 1 || ry < 1 {
+		FillRect(dst, cx-rx, cy-ry, cx+rx, cy+ry, c)
+		return
+	}
+
+	b := dst.Bounds()
+	src := image.NewUniform(c)
+	for row := max(b.Min.Y, int(math.Floor(cy-ry))); row < min(b.Max.Y, int(math.Ceil(cy+ry))); row++ {
+		dy := (float64(row) + 0.5 - cy) / ry
+		if dy*dy > 1 {
+			continue
+		}
+
+		half := rx * math.Sqrt(1-dy*dy)
+		x0 := max(b.Min.X, int(math.Ceil(cx-half-0.5)))
+		x1 := min(b.Max.X, int(math.Floor(cx+half-0.5))+1)
+		if x0 < x1 {
+			draw.Draw(dst, image.Rect(x0, row, x1, row+1), src, image.Point{}, draw.Over)
+		}
+	}
+}
+
+// SVGPath returns SVG path data tracing each of the given rings of
+// fractional pixel positions. When closed is true, each ring is closed.
+func SVGPath(rings [][][2]float64, closed bool) string {
+	parts := make([]string, 0, len(rings))
+	for _, ring := range rings {
+		var sb strings.Builder
+		for i, p := range ring {
+			if i > 0 {
+				sb.WriteString(" L")
+			} else {
+				sb.WriteString("M")
+			}
+			fmt.Fprintf(&sb, "%g %g", p[0], p[1])
+		}
+		if closed {
+			sb.WriteString(" Z")
+		}
+		parts = append(parts, sb.String())
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// SVGColor returns SVG attribute values describing c: an rgb() color and an
+// opacity.
+func SVGColor(c color.Color) (string, string) {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return fmt.Sprintf("rgb(%d,%d,%d)", n.R, n.G, n.B), fmt.Sprintf("%.3f", float64(n.A)/math.MaxUint8)
+}
@@ -0,0 +1,93 @@
+package render_test
+
+import (
+	"bytes"
+	"image/color"
+	"testing"
+
+	"github.com/chrismarget/two-dimensional-quad-tree/objects"
+	"github.com/chrismarget/two-dimensional-quad-tree/render"
+	"github.com/chrismarget/two-dimensional-quad-tree/tdqt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRaster(t *testing.T) {
+	area := tdqt.NewRectangle(tdqt.NewLimits(0, 100), tdqt.NewLimits(0, 100))
+	tree := tdqt.NewTree(area)
+
+	red := color.RGBA{R: 255, A: 255}
+	blue := color.RGBA{B: 255, A: 255}
+	green := color.RGBA{G: 255, A: 255}
+	tree.Insert(objects.NewColorRect(tdqt.NewRectangle(tdqt.NewLimits(0, 50), tdqt.NewLimits(0, 50)), red))
+	tree.Insert(objects.NewColorPoint(75, 75, blue))
+	tree.Insert(objects.NewColorLine(60, 10, 90, 10, green))
+
+	// 10 world units per pixel
+	img := render.Raster(render.NewViewport(area, 10, 10), tree.Search(area), color.White)
+
+	// the rectangle covers the bottom left quarter: image rows 5-9
+	require.Equal(t, red, img.RGBAAt(0, 9))
+	require.Equal(t, red, img.RGBAAt(4, 5))
+	require.Equal(t, color.RGBA{R: 255, G: 255, B: 255, A: 255}, img.RGBAAt(5, 5))
+	require.Equal(t, color.RGBA{R: 255, G: 255, B: 255, A: 255}, img.RGBAAt(4, 4))
+
+	require.Equal(t, blue, img.RGBAAt(7, 2))
+	require.Equal(t, green, img.RGBAAt(6, 9))
+	require.Equal(t, green, img.RGBAAt(8, 9))
+}
+
+func TestSVG(t *testing.T) {
+	area := tdqt.NewRectangle(tdqt.NewLimits(0, 100), tdqt.NewLimits(0, 100))
+	tree := tdqt.NewTree(area)
+	tree.Insert(objects.NewColorRect(tdqt.NewRectangle(tdqt.NewLimits(0, 50), tdqt.NewLimits(0, 50)), color.RGBA{R: 255, A: 255}))
+	tree.Insert(objects.NewColorLine(0, 0, 100, 100, color.RGBA{G: 128, A: 128}))
+
+	var buf bytes.Buffer
+	require.NoError(t, render.SVG(&buf, render.NewViewport(area, 200, 200), tree.Search(area)))
+
+	out := buf.String()
+	require.Contains(t, out, `<svg xmlns="http://www.w3.org/2000/svg" width="200" height="200" viewBox="0 0 200 200">`)
+	require.Contains(t, out, `<rect x="0" y="100" width="100" height="100" fill="rgb(255,0,0)" fill-opacity="1.000"/>`)
+	require.Contains(t, out, `<line x1="0" y1="200" x2="200" y2="0" stroke="rgb(0,255,0)" stroke-opacity="0.502"/>`)
+	require.Contains(t, out, "</svg>")
+}
+
+func TestRaster_Polygon(t *testing.T) {
+	area := tdqt.NewRectangle(tdqt.NewLimits(0, 10), tdqt.NewLimits(0, 10))
+	red := color.RGBA{R: 255, A: 255}
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+
+	square := func(lo, hi int64) []objects.Vertex {
+		return []objects.Vertex{{X: lo, Y: lo}, {X: hi, Y: lo}, {X: hi, Y: hi}, {X: lo, Y: hi}}
+	}
+	polygon := objects.NewColorPolygon(square(0, 10), [][]objects.Vertex{square(3, 7)}, red)
+
+	img := render.Raster(render.NewViewport(area, 10, 10), map[uint64]tdqt.Object{polygon.Hash(): polygon}, color.White)
+	require.Equal(t, red, img.RGBAAt(0, 0))
+	require.Equal(t, red, img.RGBAAt(9, 9))
+	require.Equal(t, red, img.RGBAAt(2, 5))
+	require.Equal(t, white, img.RGBAAt(5, 5))
+	require.Equal(t, white, img.RGBAAt(3, 3))
+	require.Equal(t, white, img.RGBAAt(6, 6))
+	require.Equal(t, red, img.RGBAAt(7, 7))
+}
+
+func TestRaster_Ellipse(t *testing.T) {
+	area := tdqt.NewRectangle(tdqt.NewLimits(0, 20), tdqt.NewLimits(0, 10))
+	red := color.RGBA{R: 255, A: 255}
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+
+	ellipse := objects.NewColorEllipse(10, 5, 10, 5, red)
+
+	img := render.Raster(render.NewViewport(area, 20, 10), map[uint64]tdqt.Object{ellipse.Hash(): ellipse}, color.White)
+	require.Equal(t, red, img.RGBAAt(10, 5))
+	require.Equal(t, red, img.RGBAAt(0, 5))
+	require.Equal(t, red, img.RGBAAt(19, 4))
+	require.Equal(t, red, img.RGBAAt(10, 0))
+	require.Equal(t, white, img.RGBAAt(0, 0))
+	require.Equal(t, white, img.RGBAAt(19, 9))
+
+	var buf bytes.Buffer
+	require.NoError(t, render.SVG(&buf, render.NewViewport(area, 40, 20), map[uint64]tdqt.Object{ellipse.Hash(): ellipse}))
+	require.Contains(t, buf.String(), `<ellipse cx="20" cy="10" rx="20" ry="10" fill="rgb(255,0,0)" fill-opacity="1.000"/>`)
+}
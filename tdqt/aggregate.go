@@ -0,0 +1,58 @@
+package tdqt
+
+import (
+	"reflect"
+	"slices"
+)
+
+// Aggregator describes a summary of a set of objects, such as their number or
+// their average color. Trees created WithAggregator keep a Summary of the
+// objects within each node, so that Aggregate can combine the summaries of
+// nodes lying entirely within the queried area rather than visiting their
+// objects.
+type Aggregator interface {
+	// NewSummary returns an empty Summary.
+	NewSummary() Summary
+}
+
+// Summary is a mergeable summary of a set of objects.
+type Summary interface {
+	// Add adds obj to the Summary.
+	Add(obj Object)
+
+	// Remove removes obj, which was previously added, from the Summary.
+	Remove(obj Object)
+
+	// Merge adds the objects summarized by other, which was created by the
+	// same Aggregator, to the Summary. other must not be modified.
+	Merge(other Summary)
+}
+
+// Aggregate returns a Summary, created by agg, of the distinct objects which
+// overlap area: the objects SearchAll would return. When agg was supplied to
+// the tree WithAggregator, nodes lying entirely within area contribute the
+// summaries they maintain, and only objects which cross such a node's boundary
+// are visited. Otherwise, every overlapping object is visited.
+func (t *Tree) Aggregate(area Rectangle, agg Aggregator) Summary {
+	defer t.rLock()()
+
+	result := agg.NewSummary()
+
+	var covered func(n *Tree)
+	if i := t.cfg.aggregatorIndex(agg); i >= 0 {
+		covered = func(n *Tree) { result.Merge(n.summaries[i]) }
+	}
+	newTally(area, covered, result.Add).node(t)
+
+	return result
+}
+
+// aggregatorIndex returns the position of agg among the tree's aggregators,
+// or -1 if it's not among them.
+func (c *config) aggregatorIndex(agg Aggregator) int {
+	if !reflect.TypeOf(agg).Comparable() {
+		return -1 // comparing it would panic, and WithAggregator refused it anyway
+	}
+
+	return slices.Index(c.aggregators, agg)
+}
@@ -0,0 +1,102 @@
+package tdqt
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// hashSums summarizes objects by their number and the sum of their hashes,
+// which is enough to notice an object summarized twice, or not at all.
+type hashSums struct{}
+
+func (hashSums) NewSummary() Summary { return &hashSum{} }
+
+type hashSum struct {
+	n, sum uint64
+}
+
+func (s *hashSum) Add(obj Object)    { s.n, s.sum = s.n+1, s.sum+obj.Hash() }
+func (s *hashSum) Remove(obj Object) { s.n, s.sum = s.n-1, s.sum-obj.Hash() }
+
+func (s *hashSum) Merge(other Summary) {
+	o := other.(*hashSum)
+	s.n, s.sum = s.n+o.n, s.sum+o.sum
+}
+
+// unregistered is a hashSums which isn't supplied to the tree.
+type unregistered struct{ hashSums }
+
+func TestTree_Aggregate(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		placement PlacementMode
+		seed      uint64
+	}
+
+	testCases := map[string]testCase{
+		"duplicate": {placement: PlacementDuplicate, seed: 15},
+		"loose":     {placement: PlacementLoose, seed: 16},
+	}
+
+	bounds := rect(0, 1000, 0, 1000)
+
+	for tName, tCase := range testCases {
+		t.Run(tName, func(t *testing.T) {
+			t.Parallel()
+
+			rng := rand.New(rand.NewPCG(tCase.seed, tCase.seed))
+			tree := NewTree(bounds, WithMaxObjects(4), WithMaxDepth(6), WithPlacement(tCase.placement), WithReverseIndex(),
+				WithAggregator(hashSums{}))
+
+			requireAggregates := func() {
+				t.Helper()
+				for range 50 {
+					area := randomArea(rng)
+
+					expected := &hashSum{}
+					for _, obj := range tree.SearchAll(area) {
+						expected.Add(obj)
+					}
+					require.Equal(t, expected, tree.Aggregate(area, hashSums{}), "area %s", area)
+					require.Equal(t, expected, tree.Aggregate(area, unregistered{}), "area %s", area)
+				}
+			}
+
+			for hash := range uint64(400) {
+				tree.Insert(randomBox(rng, hash))
+			}
+			requireAggregates()
+			require.Equal(t, &hashSum{n: 400, sum: 399 * 400 / 2}, tree.Aggregate(bounds, hashSums{}))
+
+			for hash := range uint64(400) {
+				if hash%3 == 0 {
+					require.True(t, tree.Remove(hash))
+				} else {
+					require.True(t, tree.Update(randomBox(rng, hash)))
+				}
+			}
+			requireAggregates()
+		})
+	}
+}
+
+func TestWithAggregator(t *testing.T) {
+	_, err := NewTreeWithOptions(rect(0, 10, 0, 10), WithAggregator(nil))
+	require.Error(t, err)
+
+	_, err = NewTreeWithOptions(rect(0, 10, 0, 10), WithAggregator(sliceAggregator{}))
+	require.Error(t, err)
+
+	// Aggregate doesn't need to recognize an aggregator to use it.
+	tree := NewTree(rect(0, 10, 0, 10), WithAggregator(hashSums{}))
+	tree.Insert(testBox{r: rect(1, 2, 1, 2), hash: 5})
+	require.Equal(t, &hashSum{n: 1, sum: 5}, tree.Aggregate(rect(0, 10, 0, 10), sliceAggregator{}))
+}
+
+// sliceAggregator can't be compared, so it can't be recognized by Aggregate.
+type sliceAggregator []int
+
+func (sliceAggregator) NewSummary() Summary { return &hashSum{} }
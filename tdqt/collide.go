@@ -0,0 +1,87 @@
+package tdqt
+
+import "iter"
+
+// Collisions yields each pair of distinct objects in the tree for which test
+// returns true, such as lines which cross one another. (These are collisions
+// between the objects' geometry, not the hash collisions described by
+// Equaler.) Only objects which share a leaf, or which are stored at a node
+// and one of its ancestors in loose mode, are tested against one another.
+// Each unordered pair is yielded once, even when the tree holds copies of an
+// object in several leaves. test must not be nil, and shouldn't depend on the
+// order of its arguments.
+//
+// The tree is read-locked (when ConcurrencyLocked) for the duration of the
+// iteration.
+func (t *Tree) Collisions(test func(a, b Object) bool) iter.Seq2[Object, Object] {
+	return func(yield func(Object, Object) bool) {
+		defer t.rLock()()
+
+		c := collider{test: test, yield: yield, seen: make(pairSet)}
+		c.walk(t, nil)
+	}
+}
+
+type collider struct {
+	test  func(Object, Object) bool
+	yield func(Object, Object) bool
+
+	// seen holds the pairs already tested which involve an object that may
+	// be met more than once (see keyedObject).
+	seen pairSet
+}
+
+// walk tests the objects stored at t against one another, and against those
+// stored at t's ancestors, before descending into t's subtrees. It returns
+// false when the caller has stopped the iteration.
+func (c *collider) walk(t *Tree, ancestors []keyedObject) bool {
+	own := t.held()
+	for i, a := range own {
+		for _, b := range ancestors {
+			if !c.pair(a, b) {
+				return false
+			}
+		}
+		for _, b := range own[i+1:] {
+			if !c.pair(a, b) {
+				return false
+			}
+		}
+	}
+
+	ancestors = append(ancestors, own...)
+	for _, st := range t.subTrees {
+		if st == nil {
+			break
+		}
+		if !c.walk(st, ancestors) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// pair tests a against b, unless the pair has been tested before. Objects
+// which lie wholly within the nodes holding them are stored only there, so a
+// pair of them is only ever met once and needn't be remembered. It returns
+// false when the caller has stopped the iteration.
+func (c *collider) pair(a, b keyedObject) bool {
+	if a.spans || b.spans {
+		if a.key > b.key {
+			a, b = b, a
+		}
+		if a.key == b.key && c.seen.has(b.key, b.obj, a.key, a.obj) {
+			return true // distinct objects sharing a key, seen in the other order
+		}
+		if !c.seen.add(a.key, a.obj, b.key, b.obj) {
+			return true
+		}
+	}
+
+	if !c.test(a.obj, b.obj) {
+		return true
+	}
+
+	return c.yield(a.obj, b.obj)
+}
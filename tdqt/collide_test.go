@@ -0,0 +1,61 @@
+package tdqt_test
+
+import (
+	"image/color"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/chrismarget/two-dimensional-quad-tree/objects"
+	"github.com/chrismarget/two-dimensional-quad-tree/tdqt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTree_Collisions(t *testing.T) {
+	everywhere := tdqt.NewRectangle(tdqt.NewLimits(0, 1000), tdqt.NewLimits(0, 1000))
+	rng := rand.New(rand.NewPCG(5, 6))
+
+	var lines []objects.ColorLine
+	for range 400 {
+		x, y := rng.Int64N(1000), rng.Int64N(1000)
+		lines = append(lines, objects.NewColorLine(x, y, min(999, x+rng.Int64N(150)), max(0, y-rng.Int64N(150)), color.RGBA{}))
+	}
+
+	pairKey := func(a, b tdqt.Object) [2]uint64 {
+		return [2]uint64{min(a.Hash(), b.Hash()), max(a.Hash(), b.Hash())}
+	}
+
+	expected := make(map[[2]uint64]int)
+	for i, a := range lines {
+		for _, b := range lines[i+1:] {
+			if a.Intersects(b) {
+				expected[pairKey(a, b)]++
+			}
+		}
+	}
+	require.NotEmpty(t, expected)
+
+	for _, placement := range []tdqt.PlacementMode{tdqt.PlacementDuplicate, tdqt.PlacementLoose} {
+		t.Run(placement.String(), func(t *testing.T) {
+			tree := tdqt.NewTree(everywhere, tdqt.WithMaxObjects(4), tdqt.WithPlacement(placement))
+			for _, l := range lines {
+				tree.Insert(l)
+			}
+
+			got := make(map[[2]uint64]int)
+			for a, b := range tree.Collisions(objects.LinesIntersect) {
+				got[pairKey(a, b)]++
+			}
+			require.Equal(t, expected, got)
+
+			// Stopping early stops the traversal.
+			var n int
+			for range tree.Collisions(objects.LinesIntersect) {
+				n++
+				if n == 3 {
+					break
+				}
+			}
+			require.Equal(t, 3, n)
+		})
+	}
+}
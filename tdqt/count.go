@@ -0,0 +1,228 @@
+package tdqt
+
+// Count returns the number of distinct objects which overlap area: the
+// number of objects SearchAll would return, without building the result.
+// Nodes lying entirely within area contribute the objects they wholly
+// contain without examining them. Only objects which cross such a node's
+// boundary (and so may be stored elsewhere too) are examined individually.
+func (t *Tree) Count(area Rectangle) uint64 {
+	defer t.rLock()()
+
+	return t.countIn(area)
+}
+
+// Density counts the objects overlapping each cell of area, divided into a
+// grid as by Rectangle.Cells. The result is indexed [row][col], with row 0
+// along the bottom of area. Each cell is counted as by Count, so an object
+// spanning several cells is counted in each of them. The result is nil
+// unless cols and rows are both positive.
+func (t *Tree) Density(area Rectangle, cols, rows int) [][]uint64 {
+	cells := area.Cells(cols, rows)
+	if cells == nil {
+		return nil
+	}
+
+	defer t.rLock()()
+
+	result := make([][]uint64, rows)
+	for i, row := range cells {
+		result[i] = make([]uint64, cols)
+		for j, cell := range row {
+			result[i][j] = t.countIn(cell)
+		}
+	}
+
+	return result
+}
+
+func (t *Tree) countIn(area Rectangle) uint64 {
+	var total uint64
+	newTally(area,
+		func(n *Tree) { total += n.contained },
+		func(Object) { total++ },
+	).node(t)
+
+	return total
+}
+
+// Any reports whether any object overlaps area. It returns as soon as one is
+// found.
+func (t *Tree) Any(area Rectangle) bool {
+	defer t.rLock()()
+
+	return t.any(area)
+}
+
+func (t *Tree) any(area Rectangle) bool {
+	if t.count == 0 || !t.overlaps(area) {
+		return false
+	}
+
+	if t.contained > 0 && area.ContainsRect(t.area) {
+		return true
+	}
+
+	for _, st := range t.subTrees {
+		if st == nil {
+			break
+		}
+		if st.any(area) {
+			return true
+		}
+	}
+
+	var found bool
+	t.each(func(_ uint64, obj Object) {
+		if !found {
+			found, _ = objectOverlaps(obj, area)
+		}
+	})
+
+	return found
+}
+
+// tally visits the objects overlapping an area, taking a shortcut through
+// nodes lying entirely within it.
+type tally struct {
+	area Rectangle
+
+	// covered accounts for the objects wholly within n, a node lying within
+	// the area. When it's nil, such nodes are visited like any other.
+	covered func(n *Tree)
+
+	// found accounts for a single object overlapping the area. It's called
+	// once per object.
+	found func(obj Object)
+
+	// seen holds the objects found individually, which may be stored in more
+	// than one node.
+	seen map[uint64][]Object
+}
+
+func newTally(area Rectangle, covered func(n *Tree), found func(obj Object)) *tally {
+	return &tally{area: area, covered: covered, found: found, seen: make(map[uint64][]Object)}
+}
+
+// node visits the objects in n's subtree which overlap the area.
+func (c *tally) node(n *Tree) {
+	if n.count == 0 || !n.overlaps(c.area) {
+		return
+	}
+
+	if c.covered != nil && c.area.ContainsRect(n.area) {
+		// Objects wholly within n can't be stored outside of it, so they
+		// can be accounted for without being seen.
+		c.covered(n)
+		if n.count > n.contained {
+			c.crossing(n, n)
+		}
+		return
+	}
+
+	for _, st := range n.subTrees {
+		if st == nil {
+			break
+		}
+		c.node(st)
+	}
+
+	n.each(func(key uint64, obj Object) {
+		if overlap, _ := objectOverlaps(obj, c.area); overlap {
+			c.see(key, obj)
+		}
+	})
+}
+
+// crossing visits the objects in d's subtree which overlap the area but
+// aren't wholly within covered, an ancestor of d (or d itself) which lies
+// within the area. Subtrees whose objects all lie within their own areas
+// are skipped.
+func (c *tally) crossing(covered, d *Tree) {
+	if d.count == d.contained && d != covered {
+		return
+	}
+
+	for _, st := range d.subTrees {
+		if st == nil {
+			break
+		}
+		c.crossing(covered, st)
+	}
+
+	d.each(func(key uint64, obj Object) {
+		overlap, contained := objectOverlaps(obj, covered.area)
+		if contained {
+			return // accounted for along with covered
+		}
+		if !overlap {
+			// Only the root can hold objects which don't overlap its area.
+			overlap, _ = objectOverlaps(obj, c.area)
+		}
+		if overlap {
+			c.see(key, obj)
+		}
+	})
+}
+
+// see passes obj to found, unless it has been seen already.
+func (c *tally) see(key uint64, obj Object) {
+	for _, o := range c.seen[key] {
+		if equal(o, obj) {
+			return
+		}
+	}
+
+	c.seen[key] = append(c.seen[key], obj)
+	c.found(obj)
+}
+
+// uncount removes the objects stored under key at nodes from the counts of
+// those nodes and their ancestors, ahead of the objects' removal.
+func uncount(key uint64, nodes []*Tree) {
+	// Distinct objects sharing the key may be stored in different nodes.
+	var objs []Object
+	for _, n := range nodes {
+		n.eachWithKey(key, func(obj Object) {
+			for _, o := range objs {
+				if equal(o, obj) {
+					return
+				}
+			}
+			objs = append(objs, obj)
+		})
+	}
+
+	for _, obj := range objs {
+		holders := make(map[*Tree]bool)
+		for _, n := range nodes {
+			var holds bool
+			n.eachWithKey(key, func(o Object) {
+				holds = holds || equal(o, obj)
+			})
+			for a := n; holds && a != nil && !holders[a]; a = a.parent {
+				holders[a] = true
+			}
+		}
+
+		for a := range holders {
+			a.count--
+			if _, contained := objectOverlaps(obj, a.area); contained {
+				a.contained--
+				for _, s := range a.summaries {
+					s.Remove(obj)
+				}
+			}
+		}
+	}
+}
+
+// eachWithKey calls fn with each object stored at this node under key.
+func (t *Tree) eachWithKey(key uint64, fn func(obj Object)) {
+	if obj, ok := t.objects[key]; ok {
+		fn(obj)
+	}
+
+	for _, obj := range t.collisions[key] {
+		fn(obj)
+	}
+}
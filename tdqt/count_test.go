@@ -0,0 +1,215 @@
+package tdqt
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// requireCountsConsistent walks the tree and checks each node's counts
+// against the objects stored in its subtree.
+func requireCountsConsistent(t *testing.T, tree *Tree) {
+	t.Helper()
+
+	var walk func(*Tree) []Object
+	walk = func(n *Tree) []Object {
+		var objs []Object
+		add := func(obj Object) {
+			for _, o := range objs {
+				if o.Hash() == obj.Hash() && equal(o, obj) {
+					return
+				}
+			}
+			objs = append(objs, obj)
+		}
+
+		n.each(func(_ uint64, obj Object) { add(obj) })
+		for _, st := range n.subTrees {
+			if st != nil {
+				for _, obj := range walk(st) {
+					add(obj)
+				}
+			}
+		}
+
+		var contained uint64
+		for _, obj := range objs {
+			if _, c := objectOverlaps(obj, n.area); c {
+				contained++
+			}
+		}
+		require.Equal(t, uint64(len(objs)), n.count, "node %s", n.area)
+		require.Equal(t, contained, n.contained, "node %s", n.area)
+
+		return objs
+	}
+	walk(tree)
+}
+
+// randomArea returns a random, non-empty Rectangle within [0,1000)².
+func randomArea(rng *rand.Rand) Rectangle {
+	x, y := rng.Int64N(1000), rng.Int64N(1000)
+	return rect(x, x+1+rng.Int64N(1000-x), y, y+1+rng.Int64N(1000-y))
+}
+
+func TestTree_Count(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		placement PlacementMode
+		seed      uint64
+	}
+
+	testCases := map[string]testCase{
+		"duplicate": {placement: PlacementDuplicate, seed: 11},
+		"loose":     {placement: PlacementLoose, seed: 12},
+	}
+
+	bounds := rect(0, 1000, 0, 1000)
+
+	for tName, tCase := range testCases {
+		t.Run(tName, func(t *testing.T) {
+			t.Parallel()
+
+			rng := rand.New(rand.NewPCG(tCase.seed, tCase.seed))
+			tree := NewTree(bounds, WithMaxObjects(4), WithMaxDepth(6), WithPlacement(tCase.placement), WithReverseIndex())
+
+			requireCounts := func() {
+				t.Helper()
+				requireCountsConsistent(t, tree)
+				for range 50 {
+					area := randomArea(rng)
+					n := len(tree.SearchAll(area))
+					require.Equal(t, uint64(n), tree.Count(area), "area %s", area)
+					require.Equal(t, n > 0, tree.Any(area), "area %s", area)
+				}
+				require.Equal(t, uint64(len(tree.SearchAll(bounds))), tree.Count(bounds))
+			}
+
+			for hash := range uint64(400) {
+				tree.Insert(randomBox(rng, hash))
+			}
+			// Inserting the same objects again changes nothing.
+			tree.Insert(testBox{r: rect(10, 20, 10, 20), hash: 1000})
+			tree.Insert(testBox{r: rect(10, 20, 10, 20), hash: 1000})
+			requireCounts()
+
+			for hash := range uint64(400) {
+				if hash%3 == 0 {
+					require.True(t, tree.Remove(hash))
+				} else {
+					require.True(t, tree.Update(randomBox(rng, hash)))
+				}
+			}
+			requireCounts()
+
+			for hash := range uint64(400) {
+				if hash%3 != 0 {
+					require.True(t, tree.Remove(hash))
+				}
+			}
+			require.True(t, tree.Remove(1000))
+			requireCountsConsistent(t, tree)
+			require.Zero(t, tree.Count(bounds))
+			require.False(t, tree.Any(bounds))
+		})
+	}
+}
+
+// distinctBox is a testBox which implements Equaler, so that boxes sharing a
+// hash are stored side by side.
+type distinctBox struct {
+	testBox
+}
+
+func (b distinctBox) Equal(o Object) bool {
+	other, ok := o.(distinctBox)
+	return ok && other.hash == b.hash && other.r.Equal(b.r)
+}
+
+func TestTree_Count_Collisions(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		placement PlacementMode
+		seed      uint64
+	}
+
+	testCases := map[string]testCase{
+		"duplicate": {placement: PlacementDuplicate, seed: 13},
+		"loose":     {placement: PlacementLoose, seed: 14},
+	}
+
+	bounds := rect(0, 1000, 0, 1000)
+
+	for tName, tCase := range testCases {
+		t.Run(tName, func(t *testing.T) {
+			t.Parallel()
+
+			rng := rand.New(rand.NewPCG(tCase.seed, tCase.seed))
+			tree := NewTree(bounds, WithMaxObjects(4), WithMaxDepth(6), WithPlacement(tCase.placement), WithReverseIndex(),
+				WithHashFunc(func(o Object) uint64 { return o.Hash() % 7 }))
+
+			for hash := range uint64(200) {
+				x, y := rng.Int64N(950), rng.Int64N(950)
+				tree.Insert(distinctBox{testBox{r: rect(x, x+1+rng.Int64N(50), y, y+1+rng.Int64N(50)), hash: hash}})
+			}
+			requireCountsConsistent(t, tree)
+			require.Equal(t, uint64(200), tree.Count(bounds))
+
+			// Removing a key removes every object sharing it.
+			require.True(t, tree.Remove(3))
+			requireCountsConsistent(t, tree)
+			require.Equal(t, uint64(len(tree.SearchAll(bounds))), tree.Count(bounds))
+			require.Less(t, tree.Count(bounds), uint64(200))
+
+			area := rect(100, 600, 200, 700)
+			require.Equal(t, uint64(len(tree.SearchAll(area))), tree.Count(area))
+		})
+	}
+}
+
+func TestTree_Density(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		placement PlacementMode
+		seed      uint64
+	}
+
+	testCases := map[string]testCase{
+		"duplicate": {placement: PlacementDuplicate, seed: 17},
+		"loose":     {placement: PlacementLoose, seed: 18},
+	}
+
+	bounds := rect(0, 1000, 0, 1000)
+
+	for tName, tCase := range testCases {
+		t.Run(tName, func(t *testing.T) {
+			t.Parallel()
+
+			rng := rand.New(rand.NewPCG(tCase.seed, tCase.seed))
+			tree := NewTree(bounds, WithMaxObjects(4), WithMaxDepth(6), WithPlacement(tCase.placement))
+
+			for hash := range uint64(400) {
+				tree.Insert(randomBox(rng, hash))
+			}
+
+			for _, area := range []Rectangle{bounds, rect(-50, 1050, -50, 1050), rect(123, 456, 78, 910)} {
+				density := tree.Density(area, 7, 5)
+				cells := area.Cells(7, 5)
+				require.Len(t, density, 5)
+				for i, row := range cells {
+					require.Len(t, density[i], 7)
+					for j, cell := range row {
+						require.Equal(t, uint64(len(tree.SearchAll(cell))), density[i][j], "cell %s", cell)
+					}
+				}
+			}
+
+			require.Nil(t, tree.Density(bounds, 0, 5))
+			require.Nil(t, tree.Density(bounds, 5, -1))
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package tdqt
+
+// Equaler is an optional interface which may be implemented by an Object
+// whose Hash isn't a unique identity. When a node already holds an object
+// with the same Hash as a newly inserted one, Equal decides whether they're
+// the same object (subject to the CollisionPolicy) or distinct objects which
+// must coexist. Objects which don't implement Equaler are considered equal
+// whenever their hashes are.
+type Equaler interface {
+	// Equal reports whether the object is the same as o. It should be
+	// reflexive and symmetric.
+	Equal(o Object) bool
+}
+
+// equal reports whether a and b, which share a Hash, are the same object.
+func equal(a, b Object) bool {
+	if e, ok := a.(Equaler); ok {
+		return e.Equal(b)
+	}
+
+	if e, ok := b.(Equaler); ok {
+		return e.Equal(a)
+	}
+
+	return true
+}
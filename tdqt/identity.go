@@ -0,0 +1,84 @@
+package tdqt
+
+// Identified is an optional interface which may be implemented by an Object
+// whose identity is independent of its geometry, such as a vehicle which
+// moves, or a feature which changes color. Identified objects are keyed by
+// ID rather than by hash, and the tree indexes the nodes holding each of
+// them, so that they can be updated or removed without searching.
+//
+// IDs share a key space with the hashes of objects which aren't Identified,
+// so callers mixing the two should choose IDs which won't collide with
+// hashes (or implement Equaler).
+type Identified interface {
+	// ID returns the object's caller-assigned identity, which must not change
+	// for the life of the object.
+	ID() uint64
+}
+
+// key returns the key under which obj is stored.
+func (c *config) key(obj Object) uint64 {
+	if id, ok := obj.(Identified); ok {
+		return id.ID()
+	}
+
+	return c.hashFunc(obj)
+}
+
+// Key returns the key under which the tree stores obj: its ID if it's
+// Identified, and otherwise its hash as computed by the tree's HashFunc (see
+// WithHashFunc). Locate and Remove take keys.
+func (t *Tree) Key(obj Object) uint64 {
+	return t.cfg.key(obj)
+}
+
+// Remove deletes the object stored under key (see Key) from the tree. Unless
+// the tree was created WithReverseIndex, only Identified objects can be
+// removed this way. Distinct objects sharing the key (see Equaler) are all
+// removed. Remove visits only the nodes holding the object, so its cost
+// doesn't depend on the object's geometry. It reports whether anything was
+// removed.
+func (t *Tree) Remove(key uint64) bool {
+	defer t.lock()()
+
+	return t.remove(key)
+}
+
+func (t *Tree) remove(key uint64) bool {
+	nodes, ok := t.cfg.index[key]
+	if !ok {
+		return false
+	}
+
+	uncount(key, nodes)
+	for _, n := range nodes {
+		delete(n.objects, key)
+		delete(n.collisions, key)
+	}
+	delete(t.cfg.index, key)
+
+	return true
+}
+
+// Update replaces the stored object with the same key as obj, regardless of
+// the CollisionPolicy. The new version may have moved: it's placed according
+// to its own geometry, and the old version is removed from every node which
+// held it. Update reports whether a previous version was found. When none
+// was, the tree is left unchanged.
+//
+// Objects which aren't Identified are keyed by hash, so they can only be
+// updated by a version which hashes the same, and only when the tree was
+// created WithReverseIndex.
+func (t *Tree) Update(obj Object) bool {
+	defer t.lock()()
+
+	key := t.cfg.key(obj)
+	if !t.remove(key) {
+		return false
+	}
+
+	e := newEntry(key, obj)
+	_, contained := e.overlaps(&t.area)
+	t.insert(&e, t.depth, contained)
+
+	return true
+}
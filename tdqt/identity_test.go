@@ -0,0 +1,85 @@
+package tdqt_test
+
+import (
+	"image/color"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/chrismarget/two-dimensional-quad-tree/objects"
+	"github.com/chrismarget/two-dimensional-quad-tree/tdqt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTree_Update(t *testing.T) {
+	everywhere := tdqt.NewRectangle(tdqt.NewLimits(0, 1000), tdqt.NewLimits(0, 1000))
+	left := tdqt.NewRectangle(tdqt.NewLimits(0, 500), tdqt.NewLimits(0, 1000))
+	right := tdqt.NewRectangle(tdqt.NewLimits(500, 1000), tdqt.NewLimits(0, 1000))
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	for _, placement := range []tdqt.PlacementMode{tdqt.PlacementDuplicate, tdqt.PlacementLoose} {
+		t.Run(placement.String(), func(t *testing.T) {
+			tree := tdqt.NewTree(everywhere, tdqt.WithMaxObjects(4), tdqt.WithPlacement(placement))
+
+			// Vehicles start on the left, and a few long roads cross the
+			// whole area.
+			for id := range uint64(200) {
+				p := objects.NewColorPoint(rng.Int64N(500), rng.Int64N(1000), color.RGBA{})
+				tree.Insert(objects.NewEntity(id, p))
+			}
+			for id := uint64(1000); id < 1010; id++ {
+				y := int64(id-1000) * 100
+				tree.Insert(objects.NewEntity(id, objects.NewColorLine(0, y, 999, y+50, color.RGBA{})))
+			}
+			require.Len(t, tree.Search(everywhere), 210)
+
+			// Every vehicle drives to the right.
+			for id := range uint64(200) {
+				p := objects.NewColorPoint(500+rng.Int64N(500), rng.Int64N(1000), color.RGBA{})
+				require.True(t, tree.Update(objects.NewEntity(id, p)))
+			}
+			require.Len(t, tree.Search(everywhere), 210)
+			require.Len(t, tree.Search(left), 10)
+			require.Len(t, tree.Search(right), 210)
+
+			// Remove everything. The tree subdivided while the objects were
+			// moving around, so this relies on the index tracking them.
+			for id := range uint64(200) {
+				require.True(t, tree.Remove(id))
+				require.False(t, tree.Remove(id))
+			}
+			for id := uint64(1000); id < 1010; id++ {
+				require.True(t, tree.Remove(id))
+			}
+			require.Empty(t, tree.Search(everywhere))
+			require.Zero(t, tree.Stats().Objects)
+		})
+	}
+}
+
+func TestTree_Insert_Identified(t *testing.T) {
+	everywhere := tdqt.NewRectangle(tdqt.NewLimits(0, 100), tdqt.NewLimits(0, 100))
+	before := objects.NewEntity(7, objects.NewColorPoint(10, 10, color.RGBA{}))
+	after := objects.NewEntity(7, objects.NewColorPoint(90, 90, color.RGBA{}))
+
+	// Inserting a new version replaces the old one, wherever it was.
+	replace := tdqt.NewTree(everywhere, tdqt.WithMaxObjects(1))
+	replace.Insert(objects.NewColorPoint(50, 50, color.RGBA{}))
+	replace.Insert(before)
+	replace.Insert(after)
+	require.Len(t, replace.Search(everywhere), 2)
+	require.Equal(t, after, replace.Search(everywhere)[7])
+
+	keep := tdqt.NewTree(everywhere, tdqt.WithMaxObjects(1), tdqt.WithCollisionPolicy(tdqt.CollisionKeep))
+	keep.Insert(objects.NewColorPoint(50, 50, color.RGBA{}))
+	keep.Insert(before)
+	keep.Insert(after)
+	require.Len(t, keep.Search(everywhere), 2)
+	require.Equal(t, before, keep.Search(everywhere)[7])
+
+	// Update ignores the collision policy, but requires a previous version.
+	require.True(t, keep.Update(after))
+	require.Equal(t, after, keep.Search(everywhere)[7])
+	require.False(t, keep.Update(objects.NewEntity(8, objects.NewColorPoint(1, 1, color.RGBA{}))))
+	require.False(t, keep.Update(objects.NewColorPoint(1, 1, color.RGBA{})))
+	require.Len(t, keep.Search(everywhere), 2)
+}
@@ -0,0 +1,58 @@
+package tdqt
+
+import "slices"
+
+// The index maps keys to the nodes holding the objects stored under them. It
+// always covers Identified objects, and covers every object when the tree is
+// created WithReverseIndex. Any code which moves objects between nodes must
+// keep it up to date: store adds entries, and nodes which give up their
+// objects (subdivide, removal) must drop theirs.
+
+// indexed reports whether obj is tracked by the index.
+func (c *config) indexed(obj Object) bool {
+	if c.reverseIndex {
+		return true
+	}
+
+	_, ok := obj.(Identified)
+	return ok
+}
+
+// addToIndex records that node t holds the object stored under key.
+func (t *Tree) addToIndex(key uint64) {
+	nodes := t.cfg.index[key]
+	if !slices.Contains(nodes, t) {
+		t.cfg.index[key] = append(nodes, t)
+	}
+}
+
+// dropFromIndex records that node t no longer holds the object stored under
+// key.
+func (t *Tree) dropFromIndex(key uint64) {
+	nodes := slices.DeleteFunc(t.cfg.index[key], func(n *Tree) bool { return n == t })
+	if len(nodes) == 0 {
+		delete(t.cfg.index, key)
+		return
+	}
+
+	t.cfg.index[key] = nodes
+}
+
+// Locate returns the areas of the nodes holding the object stored under key
+// (see Key). Unless the tree was created WithReverseIndex, only Identified
+// objects can be located. The result is nil when key isn't in the index.
+func (t *Tree) Locate(key uint64) []Rectangle {
+	defer t.rLock()()
+
+	nodes := t.cfg.index[key]
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	result := make([]Rectangle, len(nodes))
+	for i, n := range nodes {
+		result[i] = n.area
+	}
+
+	return result
+}
@@ -0,0 +1,149 @@
+package tdqt
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testBox is a filled rectangle keyed by a caller-chosen hash.
+type testBox struct {
+	r    Rectangle
+	hash uint64
+}
+
+func (b testBox) Hash() uint64 { return b.hash }
+
+func (b testBox) Overlaps(r Rectangle) (bool, bool) {
+	return b.r.Overlaps(r), r.ContainsRect(b.r)
+}
+
+// requireIndexConsistent walks the tree and checks that the index lists
+// exactly the nodes holding each key.
+func requireIndexConsistent(t *testing.T, tree *Tree) {
+	t.Helper()
+
+	expected := make(map[uint64]map[*Tree]bool)
+	var walk func(*Tree)
+	walk = func(n *Tree) {
+		n.each(func(k uint64, _ Object) {
+			if expected[k] == nil {
+				expected[k] = make(map[*Tree]bool)
+			}
+			expected[k][n] = true
+		})
+		for _, st := range n.subTrees {
+			if st != nil {
+				walk(st)
+			}
+		}
+	}
+	walk(tree)
+
+	require.Len(t, tree.cfg.index, len(expected))
+	for k, nodes := range tree.cfg.index {
+		require.Len(t, nodes, len(expected[k]), "key %d", k)
+		for _, n := range nodes {
+			require.True(t, expected[k][n], "key %d", k)
+		}
+	}
+}
+
+// randomBox returns a testBox with the given hash, lying within
+// [0,1000)x[0,1000) and at most 100 units on a side.
+func randomBox(rng *rand.Rand, hash uint64) testBox {
+	x, y := rng.Int64N(990), rng.Int64N(990)
+	w, h := 1+rng.Int64N(min(100, 1000-x)), 1+rng.Int64N(min(100, 1000-y))
+	return testBox{r: rect(x, x+w, y, y+h), hash: hash}
+}
+
+func TestTree_ReverseIndex(t *testing.T) {
+	bounds := rect(0, 1000, 0, 1000)
+
+	type testCase struct {
+		placement PlacementMode
+		seed      uint64
+	}
+
+	testCases := map[string]testCase{
+		"duplicate": {placement: PlacementDuplicate, seed: 1},
+		"loose":     {placement: PlacementLoose, seed: 2},
+	}
+
+	for tName, tCase := range testCases {
+		t.Run(tName, func(t *testing.T) {
+			t.Parallel()
+
+			rng := rand.New(rand.NewPCG(tCase.seed, tCase.seed))
+			tree := NewTree(bounds, WithMaxObjects(4), WithMaxDepth(6), WithPlacement(tCase.placement), WithReverseIndex())
+
+			boxes := make(map[uint64]testBox)
+			for hash := range uint64(500) {
+				boxes[hash] = randomBox(rng, hash)
+				tree.Insert(boxes[hash])
+			}
+			requireIndexConsistent(t, tree)
+
+			// Every located area holds the box, and together they cover it.
+			for hash, box := range boxes {
+				areas := tree.Locate(hash)
+				require.NotEmpty(t, areas)
+				var covered Rectangle
+				for _, a := range areas {
+					require.True(t, box.r.Overlaps(a))
+					covered = covered.Union(a)
+				}
+				require.True(t, covered.ContainsRect(box.r))
+			}
+			require.Nil(t, tree.Locate(12345))
+
+			// Move half of the boxes, and remove the rest.
+			for hash := range uint64(500) {
+				if hash%2 == 0 {
+					boxes[hash] = randomBox(rng, hash)
+					require.True(t, tree.Update(boxes[hash]))
+				} else {
+					require.True(t, tree.Remove(hash))
+					delete(boxes, hash)
+				}
+			}
+			requireIndexConsistent(t, tree)
+			require.Len(t, tree.Search(bounds), 250)
+			require.Equal(t, 250, len(tree.cfg.index))
+
+			for hash := range boxes {
+				require.True(t, tree.Remove(hash))
+			}
+			requireIndexConsistent(t, tree)
+			require.Empty(t, tree.Search(bounds))
+		})
+	}
+}
+
+func TestTree_Key(t *testing.T) {
+	tree := NewTree(rect(0, 100, 0, 100), WithReverseIndex(), WithHashFunc(func(o Object) uint64 { return o.Hash() + 1000 }))
+	box := testBox{r: rect(10, 20, 10, 20), hash: 1}
+	tree.Insert(box)
+
+	// Objects are located and removed by the tree's key, not their own hash.
+	require.Equal(t, uint64(1001), tree.Key(box))
+	require.Nil(t, tree.Locate(box.Hash()))
+	require.Len(t, tree.Locate(tree.Key(box)), 1)
+	require.False(t, tree.Remove(box.Hash()))
+	require.True(t, tree.Remove(tree.Key(box)))
+	require.Empty(t, tree.Search(rect(0, 100, 0, 100)))
+}
+
+func TestTree_IndexIdentifiedOnly(t *testing.T) {
+	tree := NewTree(rect(0, 100, 0, 100), WithMaxObjects(1))
+	tree.Insert(testBox{r: rect(0, 10, 0, 10), hash: 1})
+	tree.Insert(testBox{r: rect(50, 60, 50, 60), hash: 2})
+
+	// Without WithReverseIndex, objects which aren't Identified aren't
+	// indexed, so they can't be located or removed.
+	require.Empty(t, tree.cfg.index)
+	require.Nil(t, tree.Locate(1))
+	require.False(t, tree.Remove(1))
+	require.Len(t, tree.Search(rect(0, 100, 0, 100)), 2)
+}
@@ -0,0 +1,295 @@
+package tdqt
+
+import (
+	"iter"
+	"unsafe"
+)
+
+// Join yields each pair of objects, the first from a and the second from b,
+// for which pred returns true. Both trees are traversed together, and pairs
+// of nodes whose areas don't overlap are skipped along with everything
+// beneath them, so pred is only consulted for objects which might interact.
+// Each pair is yielded once, even when the trees hold copies of an object in
+// several leaves. pred must not be nil.
+//
+// An object which extends beyond its tree's area may meet objects of the
+// other tree outside of any pair of nodes the traversal compares. Such
+// objects are paired with every object in the other tree, so trees which
+// hold many of them join slowly. Finding them takes a pass over every object
+// in a tree using PlacementDuplicate.
+//
+// Both trees are read-locked (when ConcurrencyLocked) for the duration of
+// the iteration. a and b may be the same tree, in which case each object is
+// also paired with itself.
+func Join(a, b *Tree, pred func(Object, Object) bool) iter.Seq2[Object, Object] {
+	return func(yield func(Object, Object) bool) {
+		defer rLockBoth(a, b)()
+
+		j := joiner{pred: pred, yield: yield, seen: make(pairSet)}
+		if !j.join(a, b, false, false) {
+			return
+		}
+
+		for _, ao := range a.overflowing() {
+			if !j.pairWithAll(ao, b, false) {
+				return
+			}
+		}
+		for _, bo := range b.overflowing() {
+			if !j.pairWithAll(bo, a, true) {
+				return
+			}
+		}
+	}
+}
+
+// rLockBoth read-locks a and b, and returns a function which unlocks both.
+// The locks are taken in a consistent order, so that concurrent joins of the
+// same trees in either order can't deadlock with one another (and with
+// writers waiting on either tree). Trees sharing a config share a lock,
+// which is taken once.
+func rLockBoth(a, b *Tree) func() {
+	if a.cfg == b.cfg {
+		return a.rLock()
+	}
+
+	if uintptr(unsafe.Pointer(a.cfg)) > uintptr(unsafe.Pointer(b.cfg)) {
+		a, b = b, a
+	}
+	unlockA := a.rLock()
+	unlockB := b.rLock()
+
+	return func() {
+		unlockB()
+		unlockA()
+	}
+}
+
+type joiner struct {
+	pred  func(Object, Object) bool
+	yield func(Object, Object) bool
+
+	// seen holds the candidate pairs already considered which involve an
+	// object that may be met more than once (see keyedObject).
+	seen pairSet
+}
+
+// keyedObject is an Object along with the key under which it's stored. spans
+// is set when the object isn't wholly within the area of the node it was
+// found at, in which case the tree may hold it elsewhere too.
+type keyedObject struct {
+	key   uint64
+	obj   Object
+	spans bool
+}
+
+// held returns the objects stored at t itself.
+func (t *Tree) held() []keyedObject {
+	var result []keyedObject
+	t.each(func(key uint64, obj Object) {
+		_, contained := objectOverlaps(obj, t.area)
+		result = append(result, keyedObject{key: key, obj: obj, spans: !contained})
+	})
+
+	return result
+}
+
+// join considers the objects stored in a and b, and in their subtrees. When
+// aOnly (bOnly) is set, only the objects stored at a (b) itself are
+// considered. It returns false when the caller has stopped the iteration.
+func (j *joiner) join(a, b *Tree, aOnly, bOnly bool) bool {
+	if !a.area.Overlaps(b.area) {
+		return true
+	}
+
+	if !j.pairObjects(a, b) {
+		return false
+	}
+
+	aSplit := !aOnly && a.subTrees[0] != nil
+	bSplit := !bOnly && b.subTrees[0] != nil
+
+	switch {
+	case aSplit && bSplit:
+		for _, sa := range a.subTrees {
+			if sa == nil {
+				break
+			}
+			for _, sb := range b.subTrees {
+				if sb == nil {
+					break
+				}
+				if !j.join(sa, sb, false, false) {
+					return false
+				}
+			}
+		}
+
+		// In loose mode, internal nodes hold objects of their own. Pair
+		// them with the other tree's subtrees.
+		if b.holdsObjects() {
+			for _, sa := range a.subTrees {
+				if sa != nil && !j.join(sa, b, false, true) {
+					return false
+				}
+			}
+		}
+		if a.holdsObjects() {
+			for _, sb := range b.subTrees {
+				if sb != nil && !j.join(a, sb, true, false) {
+					return false
+				}
+			}
+		}
+	case aSplit:
+		for _, sa := range a.subTrees {
+			if sa != nil && !j.join(sa, b, false, bOnly) {
+				return false
+			}
+		}
+	case bSplit:
+		for _, sb := range b.subTrees {
+			if sb != nil && !j.join(a, sb, aOnly, false) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// pairObjects considers each pair of objects stored at a and b themselves.
+// It returns false when the caller has stopped the iteration.
+func (j *joiner) pairObjects(a, b *Tree) bool {
+	if !a.holdsObjects() || !b.holdsObjects() {
+		return true
+	}
+
+	bHeld := b.held()
+	for _, ao := range a.held() {
+		for _, bo := range bHeld {
+			if j.first(ao, bo) && j.pred(ao.obj, bo.obj) && !j.yield(ao.obj, bo.obj) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// pairWithAll considers pairing o, which overflows its tree's area, with
+// every object in t's subtree: o is the first member of each pair unless
+// reversed. It returns false when the caller has stopped the iteration.
+func (j *joiner) pairWithAll(o keyedObject, t *Tree, reversed bool) bool {
+	more := true
+	t.each(func(k uint64, obj Object) {
+		other := keyedObject{key: k, obj: obj}
+		switch {
+		case !more:
+		case reversed:
+			if j.first(other, o) && j.pred(obj, o.obj) {
+				more = j.yield(obj, o.obj)
+			}
+		default:
+			if j.first(o, other) && j.pred(o.obj, obj) {
+				more = j.yield(o.obj, obj)
+			}
+		}
+	})
+	if !more {
+		return false
+	}
+
+	for _, st := range t.subTrees {
+		if st == nil {
+			break
+		}
+		if !j.pairWithAll(o, st, reversed) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// first reports whether the pair (a, b) is being considered for the first
+// time. Objects which lie wholly within the nodes they were found at are
+// stored only there, so a pair of them is only ever met once and needn't be
+// remembered.
+func (j *joiner) first(a, b keyedObject) bool {
+	if !a.spans && !b.spans {
+		return true
+	}
+
+	return j.seen.add(a.key, a.obj, b.key, b.obj)
+}
+
+// pairSet holds ordered pairs of objects, keyed by the objects' keys.
+type pairSet map[[2]uint64][][2]Object
+
+// has reports whether the pair (ao, bo) is in the set.
+func (s pairSet) has(ak uint64, ao Object, bk uint64, bo Object) bool {
+	for _, pair := range s[[2]uint64{ak, bk}] {
+		if equal(pair[0], ao) && equal(pair[1], bo) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// add adds the pair (ao, bo) to the set, and reports whether it was absent.
+func (s pairSet) add(ak uint64, ao Object, bk uint64, bo Object) bool {
+	if s.has(ak, ao, bk, bo) {
+		return false
+	}
+
+	keys := [2]uint64{ak, bk}
+	s[keys] = append(s[keys], [2]Object{ao, bo})
+
+	return true
+}
+
+// holdsObjects reports whether any objects are stored at t itself.
+func (t *Tree) holdsObjects() bool {
+	return len(t.objects) > 0 || len(t.collisions) > 0
+}
+
+// overflowing returns the distinct objects in the tree which aren't wholly
+// within its area. In loose mode, these are all stored at the root.
+func (t *Tree) overflowing() []keyedObject {
+	var result []keyedObject
+	seen := make(map[uint64][]Object) // objects may be stored in several leaves
+	var walk func(n *Tree)
+	walk = func(n *Tree) {
+	held:
+		for _, o := range n.held() {
+			if !o.spans {
+				continue // within n, so within t
+			}
+			if _, contained := objectOverlaps(o.obj, t.area); contained {
+				continue
+			}
+			for _, s := range seen[o.key] {
+				if equal(s, o.obj) {
+					continue held
+				}
+			}
+			seen[o.key] = append(seen[o.key], o.obj)
+			result = append(result, o)
+		}
+
+		if t.cfg.placement == PlacementLoose {
+			return
+		}
+		for _, st := range n.subTrees {
+			if st == nil {
+				break
+			}
+			walk(st)
+		}
+	}
+	walk(t)
+
+	return result
+}
@@ -0,0 +1,225 @@
+package tdqt_test
+
+import (
+	"image/color"
+	"math/rand/v2"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chrismarget/two-dimensional-quad-tree/objects"
+	"github.com/chrismarget/two-dimensional-quad-tree/tdqt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJoin(t *testing.T) {
+	everywhere := tdqt.NewRectangle(tdqt.NewLimits(0, 1000), tdqt.NewLimits(0, 1000))
+	rng := rand.New(rand.NewPCG(3, 4))
+
+	var lines []objects.ColorLine
+	for range 300 {
+		x, y := rng.Int64N(1000), rng.Int64N(1000)
+		lines = append(lines, objects.NewColorLine(x, y, min(999, x+rng.Int64N(100)), min(999, y+rng.Int64N(100)), color.RGBA{}))
+	}
+	var regions []objects.ColorRect
+	for range 100 {
+		x, y := rng.Int64N(950), rng.Int64N(950)
+		r := tdqt.NewRectangle(tdqt.NewLimits(x, x+1+rng.Int64N(50)), tdqt.NewLimits(y, y+1+rng.Int64N(50)))
+		regions = append(regions, objects.NewColorRect(r, color.RGBA{}))
+	}
+
+	crosses := func(a, b tdqt.Object) bool {
+		overlaps, _ := a.Overlaps(b.(objects.ColorRect).Bounds())
+		return overlaps
+	}
+
+	expected := make(map[[2]uint64]int)
+	for _, l := range lines {
+		for _, r := range regions {
+			if crosses(l, r) {
+				expected[[2]uint64{l.Hash(), r.Hash()}]++
+			}
+		}
+	}
+	require.NotEmpty(t, expected)
+
+	placements := []tdqt.PlacementMode{tdqt.PlacementDuplicate, tdqt.PlacementLoose}
+	for _, pa := range placements {
+		for _, pb := range placements {
+			t.Run(pa.String()+"_"+pb.String(), func(t *testing.T) {
+				a := tdqt.NewTree(everywhere, tdqt.WithMaxObjects(4), tdqt.WithPlacement(pa))
+				for _, l := range lines {
+					a.Insert(l)
+				}
+				b := tdqt.NewTree(everywhere, tdqt.WithMaxObjects(2), tdqt.WithPlacement(pb))
+				for _, r := range regions {
+					b.Insert(r)
+				}
+
+				got := make(map[[2]uint64]int)
+				for l, r := range tdqt.Join(a, b, crosses) {
+					got[[2]uint64{l.Hash(), r.Hash()}]++
+				}
+				require.Equal(t, expected, got)
+
+				// Stopping early stops the traversal.
+				var n int
+				for range tdqt.Join(a, b, crosses) {
+					n++
+					if n == 5 {
+						break
+					}
+				}
+				require.Equal(t, 5, n)
+			})
+		}
+	}
+}
+
+func TestJoin_Self(t *testing.T) {
+	everywhere := tdqt.NewRectangle(tdqt.NewLimits(0, 100), tdqt.NewLimits(0, 100))
+	tree := tdqt.NewTree(everywhere, tdqt.WithMaxObjects(1))
+	tree.Insert(objects.NewColorRect(tdqt.NewRectangle(tdqt.NewLimits(0, 60), tdqt.NewLimits(0, 60)), color.RGBA{}))
+	tree.Insert(objects.NewColorRect(tdqt.NewRectangle(tdqt.NewLimits(40, 100), tdqt.NewLimits(40, 100)), color.RGBA{}))
+	tree.Insert(objects.NewColorRect(tdqt.NewRectangle(tdqt.NewLimits(70, 100), tdqt.NewLimits(0, 30)), color.RGBA{}))
+
+	overlapping := func(a, b tdqt.Object) bool {
+		return a.(objects.ColorRect).Bounds().Overlaps(b.(objects.ColorRect).Bounds())
+	}
+
+	// Each rectangle overlaps itself, and the first two overlap each other
+	// (in both orders).
+	var n int
+	for range tdqt.Join(tree, tree, overlapping) {
+		n++
+	}
+	require.Equal(t, 5, n)
+}
+
+func TestJoin_Overflow(t *testing.T) {
+	left := tdqt.NewRectangle(tdqt.NewLimits(0, 100), tdqt.NewLimits(0, 100))
+	right := tdqt.NewRectangle(tdqt.NewLimits(100, 200), tdqt.NewLimits(0, 100))
+	overlapping := func(a, b tdqt.Object) bool {
+		return a.(objects.ColorRect).Bounds().Overlaps(b.(objects.ColorRect).Bounds())
+	}
+	newRect := func(xMin, xMax, yMin, yMax int64) objects.ColorRect {
+		return objects.NewColorRect(tdqt.NewRectangle(tdqt.NewLimits(xMin, xMax), tdqt.NewLimits(yMin, yMax)), color.RGBA{})
+	}
+
+	// The first rectangle pokes out of the left tree's area, overlapping the
+	// second within the right tree's area. The trees' areas don't overlap.
+	poking := newRect(90, 110, 45, 55)
+	crossed := newRect(105, 106, 40, 60)
+	inside := newRect(10, 20, 10, 20)
+
+	// Random rectangles which start within their tree's area, but may leave
+	// it.
+	rng := rand.New(rand.NewPCG(5, 6))
+	randomRects := func(area tdqt.Rectangle) []objects.ColorRect {
+		xLimits, yLimits := area.Limits()
+		var result []objects.ColorRect
+		for range 200 {
+			x := xLimits.Min() + rng.Int64N(xLimits.Max()-xLimits.Min())
+			y := yLimits.Min() + rng.Int64N(yLimits.Max()-yLimits.Min())
+			w, h := 1+rng.Int64N(30), 1+rng.Int64N(30)
+			result = append(result, newRect(x-rng.Int64N(w), x+w, y-rng.Int64N(h), y+h))
+		}
+		return result
+	}
+	aRects, bRects := randomRects(left), randomRects(right)
+
+	expected := make(map[[2]uint64]int)
+	for _, l := range aRects {
+		for _, r := range bRects {
+			if overlapping(l, r) {
+				expected[[2]uint64{l.Hash(), r.Hash()}]++
+			}
+		}
+	}
+	require.NotEmpty(t, expected)
+
+	placements := []tdqt.PlacementMode{tdqt.PlacementDuplicate, tdqt.PlacementLoose}
+	for _, pa := range placements {
+		for _, pb := range placements {
+			t.Run(pa.String()+"_"+pb.String(), func(t *testing.T) {
+				a := tdqt.NewTree(left, tdqt.WithMaxObjects(4), tdqt.WithPlacement(pa))
+				b := tdqt.NewTree(right, tdqt.WithMaxObjects(4), tdqt.WithPlacement(pb))
+				for i := range aRects {
+					a.Insert(aRects[i])
+					b.Insert(bRects[i])
+				}
+
+				got := make(map[[2]uint64]int)
+				for l, r := range tdqt.Join(a, b, overlapping) {
+					got[[2]uint64{l.Hash(), r.Hash()}]++
+				}
+				require.Equal(t, expected, got)
+
+				a = tdqt.NewTree(left, tdqt.WithMaxObjects(1), tdqt.WithPlacement(pa))
+				a.Insert(poking)
+				a.Insert(inside)
+				b = tdqt.NewTree(right, tdqt.WithMaxObjects(1), tdqt.WithPlacement(pb))
+				b.Insert(crossed)
+				b.Insert(newRect(150, 190, 10, 90))
+
+				var pairs [][2]uint64
+				for l, r := range tdqt.Join(a, b, overlapping) {
+					pairs = append(pairs, [2]uint64{l.Hash(), r.Hash()})
+				}
+				require.Equal(t, [][2]uint64{{poking.Hash(), crossed.Hash()}}, pairs)
+
+				pairs = nil
+				for l, r := range tdqt.Join(b, a, overlapping) {
+					pairs = append(pairs, [2]uint64{l.Hash(), r.Hash()})
+				}
+				require.Equal(t, [][2]uint64{{crossed.Hash(), poking.Hash()}}, pairs)
+			})
+		}
+	}
+}
+
+func TestJoin_Concurrent(t *testing.T) {
+	everywhere := tdqt.NewRectangle(tdqt.NewLimits(0, 1000), tdqt.NewLimits(0, 1000))
+	a := tdqt.NewTree(everywhere, tdqt.WithMaxObjects(4), tdqt.WithConcurrency(tdqt.ConcurrencyLocked))
+	b := tdqt.NewTree(everywhere, tdqt.WithMaxObjects(4), tdqt.WithConcurrency(tdqt.ConcurrencyLocked))
+	for i := range int64(50) {
+		a.Insert(objects.NewColorPoint(i*20, i*20, color.RGBA{}))
+		b.Insert(objects.NewColorPoint(i*20, 1000-i*20, color.RGBA{}))
+	}
+	always := func(_, _ tdqt.Object) bool { return true }
+
+	// Joins in both orders, racing with writers on both trees, must not
+	// deadlock.
+	var wg sync.WaitGroup
+	for g := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range int64(200) {
+				switch g {
+				case 0:
+					for range tdqt.Join(a, b, always) {
+					}
+				case 1:
+					for range tdqt.Join(b, a, always) {
+					}
+				case 2:
+					a.Insert(objects.NewColorPoint(i, i, color.RGBA{}))
+				case 3:
+					b.Insert(objects.NewColorPoint(i, i, color.RGBA{}))
+				}
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Minute):
+		t.Fatal("deadlock")
+	}
+}
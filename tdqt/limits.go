@@ -1,6 +1,10 @@
 package tdqt
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+	"math/bits"
+)
 
 // Limits define upper and lower bounds in one dimension. Limits work like a
 // slice index, so min: 0 and max: 5 covers 5 values: 0, 1, 2, 3, 4. Value 5 is
@@ -27,32 +31,230 @@ func (l *Limits) String() string {
 	return fmt.Sprintf("%d-%d", l.min, l.max)
 }
 
-func (l *Limits) cannotSubdivide() bool {
-	return l.min+1 == l.max
+// width returns the number of values covered by l. Because l.max may be as
+// large as math.MaxInt64 while l.min is math.MinInt64, the result is unsigned.
+func (l *Limits) width() uint64 {
+	return uint64(l.max) - uint64(l.min)
 }
 
-func (l *Limits) overlaps(b Limits) bool {
-	if l.min > b.max {
-		return false // l is too far to the right
+// divide splits l into n consecutive Limits whose widths differ by at most
+// one. n must be positive.
+func (l *Limits) divide(n int) []Limits {
+	w := l.width()
+	bound := func(i int) int64 {
+		// min + floor(w*i/n), without overflowing
+		hi, lo := bits.Mul64(w, uint64(i))
+		q, _ := bits.Div64(hi, lo, uint64(n))
+		return int64(uint64(l.min) + q)
 	}
 
-	if l.max <= b.min {
-		return false // l is too far to the left
+	result := make([]Limits, n)
+	for i := range result {
+		result[i] = Limits{bound(i), bound(i + 1), l.midpointFunc}
 	}
 
-	return true
+	return result
 }
 
+// cannotSubdivide indicates whether splitting l would produce a range
+// narrower than minSize.
+func (l *Limits) cannotSubdivide(minSize uint64) bool {
+	return l.width()/2 < minSize
+}
+
+// clampSplit returns v adjusted so that splitting l at v produces two ranges,
+// neither of which is narrower than minSize. It must only be called when
+// cannotSubdivide(minSize) is false.
+func (l *Limits) clampSplit(v int64, minSize uint64) int64 {
+	// wrapping unsigned arithmetic is safe: the results lie within l
+	lo := int64(uint64(l.min) + minSize)
+	hi := int64(uint64(l.max) - minSize)
+
+	return min(max(v, lo), hi)
+}
+
+// expand returns a copy of l with d subtracted from min and added to max,
+// saturating at the limits of int64. If a negative d would invert the range,
+// it collapses to an empty range at its midpoint.
+func (l *Limits) expand(d int64) Limits {
+	result := *l
+
+	if d >= 0 {
+		result.min = saturatingSub(l.min, d)
+		result.max = saturatingAdd(l.max, d)
+		return result
+	}
+
+	if l.width() <= 2*uint64(-(d+1))+1 { // 2*|d| without overflowing
+		mid := floorMidpoint(l.min, l.max)
+		result.min, result.max = mid, mid
+		return result
+	}
+
+	result.min = l.min - d
+	result.max = l.max + d
+	return result
+}
+
+func (l *Limits) empty() bool {
+	return l.min >= l.max
+}
+
+// Overlaps indicates whether l and b have at least one value in common. Like
+// Contains, it treats max as exclusive, so ranges which merely touch (l.max
+// equals b.min) do not overlap. Empty ranges overlap nothing.
+func (l *Limits) Overlaps(b Limits) bool {
+	return l.min < b.max && b.min < l.max && !l.empty() && !b.empty()
+}
+
+// Touches indicates whether l and b are adjacent: they share no values, but
+// one begins where the other ends. Empty ranges touch nothing.
+func (l *Limits) Touches(b Limits) bool {
+	if l.empty() || b.empty() {
+		return false
+	}
+
+	return l.max == b.min || b.max == l.min
+}
+
+// Disjoint indicates whether l and b have no values in common. It is the
+// opposite of Overlaps.
+func (l *Limits) Disjoint(b Limits) bool {
+	return !l.Overlaps(b)
+}
+
+// ContainsLimits indicates whether every value in b is also in l. An empty b
+// is contained by any Limits.
+func (l *Limits) ContainsLimits(b Limits) bool {
+	if b.empty() {
+		return true
+	}
+
+	return l.min <= b.min && b.max <= l.max
+}
+
+// Within indicates whether every value in l is also in b. It is the converse
+// of ContainsLimits.
+func (l *Limits) Within(b Limits) bool {
+	return b.ContainsLimits(*l)
+}
+
+// Relation returns the Allen interval relation which describes how l is
+// positioned relative to b. The result is RelationUndefined if either range
+// is empty.
+func (l *Limits) Relation(b Limits) Relation {
+	if l.empty() || b.empty() {
+		return RelationUndefined
+	}
+
+	switch {
+	case l.max < b.min:
+		return RelationBefore
+	case l.max == b.min:
+		return RelationMeets
+	case b.max < l.min:
+		return RelationAfter
+	case b.max == l.min:
+		return RelationMetBy
+	}
+
+	// l and b overlap
+	switch {
+	case l.min == b.min && l.max == b.max:
+		return RelationEqual
+	case l.min == b.min && l.max < b.max:
+		return RelationStarts
+	case l.min == b.min:
+		return RelationStartedBy
+	case l.max == b.max && l.min > b.min:
+		return RelationFinishes
+	case l.max == b.max:
+		return RelationFinishedBy
+	case l.min > b.min && l.max < b.max:
+		return RelationDuring
+	case l.min < b.min && l.max > b.max:
+		return RelationContains
+	case l.min < b.min:
+		return RelationOverlaps
+	}
+
+	return RelationOverlappedBy
+}
+
+// midpoint returns the point at which l would be split, as chosen by its
+// midpointFunc. Limits built without one (such as the zero value) use
+// defaultMidpoint.
 func (l *Limits) midpoint() int64 {
+	if l.midpointFunc == nil {
+		return defaultMidpoint(l.min, l.max)
+	}
+
 	return l.midpointFunc(l.min, l.max)
 }
 
+// defaultMidpoint returns the midpoint of a and b (rounded up) without risk of
+// overflow.
+func defaultMidpoint(a, b int64) int64 {
+	return (a | b) - ((a ^ b) >> 1)
+}
+
+// floorMidpoint returns the midpoint of a and b (rounded down) without risk
+// of overflow.
+func floorMidpoint(a, b int64) int64 {
+	return (a & b) + ((a ^ b) >> 1)
+}
+
+func saturatingAdd(a, b int64) int64 {
+	if b > 0 && a > math.MaxInt64-b {
+		return math.MaxInt64
+	}
+	if b < 0 && a < math.MinInt64-b {
+		return math.MinInt64
+	}
+	return a + b
+}
+
+func saturatingSub(a, b int64) int64 {
+	if b == math.MinInt64 {
+		if a >= 0 {
+			return math.MaxInt64
+		}
+		return a - b
+	}
+	return saturatingAdd(a, -b)
+}
+
+func (l *Limits) valid() error {
+	if l.min > l.max {
+		return fmt.Errorf("invalid limits: min (%d) exceeds max (%d)", l.min, l.max)
+	}
+
+	return nil
+}
+
+// NewLimitsE returns Limits covering min (inclusive) through max (exclusive).
+// An error is returned if the range is inverted (min > max).
+func NewLimitsE(min, max int64) (Limits, error) {
+	result := Limits{
+		min:          min,
+		max:          max,
+		midpointFunc: defaultMidpoint,
+	}
+
+	if err := result.valid(); err != nil {
+		return Limits{}, err
+	}
+
+	return result, nil
+}
+
+// NewLimits is a convenience wrapper around NewLimitsE which panics rather
+// than returning an error.
 func NewLimits(min, max int64) Limits {
-	return Limits{
-		min: min,
-		max: max,
-		midpointFunc: func(a, b int64) int64 {
-			return (a | b) - ((a ^ b) >> 1)
-		},
+	result, err := NewLimitsE(min, max)
+	if err != nil {
+		panic(err)
 	}
+
+	return result
 }
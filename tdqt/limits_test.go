@@ -143,3 +143,173 @@ func TestLimits_Midpoint_int64(t *testing.T) {
 // 	}
 //
 // }
+
+func TestNewLimitsE(t *testing.T) {
+	type testCase struct {
+		min    int64
+		max    int64
+		expErr bool
+	}
+
+	testCases := map[string]testCase{
+		"empty":    {min: 0, max: 0},
+		"unit":     {min: 0, max: 1},
+		"full":     {min: math.MinInt64, max: math.MaxInt64},
+		"inverted": {min: 1, max: 0, expErr: true},
+		"extreme":  {min: math.MaxInt64, max: math.MinInt64, expErr: true},
+	}
+
+	for tName, tCase := range testCases {
+		t.Run(tName, func(t *testing.T) {
+			t.Parallel()
+
+			l, err := NewLimitsE(tCase.min, tCase.max)
+			if tCase.expErr {
+				require.Error(t, err)
+				require.Panics(t, func() { NewLimits(tCase.min, tCase.max) })
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tCase.min, l.Min())
+			require.Equal(t, tCase.max, l.Max())
+		})
+	}
+}
+
+func TestNewRectangleE(t *testing.T) {
+	good := NewLimits(0, 10)
+	bad := Limits{min: 10, max: 0}
+
+	_, err := NewRectangleE(good, good)
+	require.NoError(t, err)
+
+	_, err = NewRectangleE(bad, good)
+	require.Error(t, err)
+
+	_, err = NewRectangleE(good, bad)
+	require.Error(t, err)
+
+	require.Panics(t, func() { NewRectangle(good, bad) })
+}
+
+func TestLimits_Relations(t *testing.T) {
+	// Interval relations depend only on the ordering of the endpoints, so a
+	// brute force check on small ranks, mapped onto values which include the
+	// extremes of int64, covers every case.
+	values := []int64{math.MinInt64, math.MinInt64 + 1, -1, 0, 1, math.MaxInt64 - 1, math.MaxInt64}
+
+	type set map[int]bool
+	members := func(lo, hi int) set {
+		result := make(set)
+		for i := lo; i < hi; i++ {
+			result[i] = true
+		}
+		return result
+	}
+	subset := func(a, b set) bool {
+		for i := range a {
+			if !b[i] {
+				return false
+			}
+		}
+		return true
+	}
+	intersect := func(a, b set) bool {
+		for i := range a {
+			if b[i] {
+				return true
+			}
+		}
+		return false
+	}
+
+	// bruteRelation determines the Allen relation using the first and last
+	// (inclusive) members of each range.
+	bruteRelation := func(lLo, lHi, bLo, bHi int) Relation {
+		if lLo >= lHi || bLo >= bHi {
+			return RelationUndefined
+		}
+		lFirst, lLast, bFirst, bLast := lLo, lHi-1, bLo, bHi-1
+		switch {
+		case lLast+1 < bFirst:
+			return RelationBefore
+		case lLast+1 == bFirst:
+			return RelationMeets
+		case bLast+1 < lFirst:
+			return RelationAfter
+		case bLast+1 == lFirst:
+			return RelationMetBy
+		case lFirst == bFirst && lLast == bLast:
+			return RelationEqual
+		case lFirst == bFirst && lLast < bLast:
+			return RelationStarts
+		case lFirst == bFirst && lLast > bLast:
+			return RelationStartedBy
+		case lLast == bLast && lFirst > bFirst:
+			return RelationFinishes
+		case lLast == bLast && lFirst < bFirst:
+			return RelationFinishedBy
+		case lFirst > bFirst && lLast < bLast:
+			return RelationDuring
+		case lFirst < bFirst && lLast > bLast:
+			return RelationContains
+		case lFirst < bFirst:
+			return RelationOverlaps
+		}
+		return RelationOverlappedBy
+	}
+
+	seen := make(map[Relation]bool)
+	for lLo := range values {
+		for lHi := lLo; lHi < len(values); lHi++ {
+			for bLo := range values {
+				for bHi := bLo; bHi < len(values); bHi++ {
+					l := NewLimits(values[lLo], values[lHi])
+					b := NewLimits(values[bLo], values[bHi])
+					lSet, bSet := members(lLo, lHi), members(bLo, bHi)
+
+					msg := fmt.Sprintf("l: %s b: %s", l.String(), b.String())
+
+					overlap := intersect(lSet, bSet)
+					require.Equal(t, overlap, l.Overlaps(b), msg)
+					require.Equal(t, !overlap, l.Disjoint(b), msg)
+					require.Equal(t, subset(bSet, lSet), l.ContainsLimits(b), msg)
+					require.Equal(t, subset(lSet, bSet), l.Within(b), msg)
+					require.Equal(t, len(lSet) > 0 && len(bSet) > 0 && !overlap && (lHi == bLo || bHi == lLo), l.Touches(b), msg)
+
+					relation := bruteRelation(lLo, lHi, bLo, bHi)
+					require.Equal(t, relation, l.Relation(b), msg)
+					require.Equal(t, relation.Inverse(), b.Relation(l), msg)
+					seen[relation] = true
+				}
+			}
+		}
+	}
+
+	// every relation should have turned up
+	require.Len(t, seen, int(RelationAfter)+1)
+}
+
+func TestLimits_Overlaps_HalfOpen(t *testing.T) {
+	// a range which begins at another's exclusive max doesn't overlap it
+	a := NewLimits(0, 10)
+	b := NewLimits(10, 20)
+	require.False(t, a.Overlaps(b))
+	require.False(t, b.Overlaps(a))
+	require.True(t, a.Touches(b))
+
+	ra := NewRectangle(a, a)
+	rb := NewRectangle(b, a)
+	require.False(t, ra.Overlaps(rb))
+	require.False(t, rb.Overlaps(ra))
+
+	top := NewLimits(math.MaxInt64-1, math.MaxInt64)
+	bottom := NewLimits(math.MinInt64, math.MinInt64+1)
+	full := NewLimits(math.MinInt64, math.MaxInt64)
+	require.True(t, full.Overlaps(top))
+	require.True(t, full.Overlaps(bottom))
+	require.False(t, top.Overlaps(bottom))
+	require.Equal(t, RelationFinishes, top.Relation(full))
+	require.Equal(t, RelationStarts, bottom.Relation(full))
+}
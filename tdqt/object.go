@@ -1,5 +1,7 @@
 package tdqt
 
+import "math"
+
 type Object interface {
 	// Hash returns an ID suitable for use as a map key
 	Hash() uint64
@@ -9,3 +11,76 @@ type Object interface {
 	// *entirely contained within* the specified Rectangle (the second boolean)
 	Overlaps(Rectangle) (bool, bool)
 }
+
+// Bounded is an optional interface which may be implemented by an Object to
+// report its axis-aligned bounding box. The tree consults the bounding box
+// before calling Overlaps: nodes which the box misses are rejected and nodes
+// which wholly contain the box are accepted, leaving the (potentially costly)
+// exact test for boundary cases only.
+type Bounded interface {
+	// Bounds returns the smallest Rectangle which contains the object.
+	Bounds() Rectangle
+}
+
+// objectOverlaps answers the same question as obj.Overlaps(r), taking the
+// Bounded shortcut when it's available.
+func objectOverlaps(obj Object, r Rectangle) (bool, bool) {
+	if b, ok := obj.(Bounded); ok {
+		bounds := b.Bounds()
+		return boundsOverlaps(obj, &bounds, &r)
+	}
+
+	return obj.Overlaps(r)
+}
+
+// boundsOverlaps answers the same question as obj.Overlaps(r), using obj's
+// bounding box to avoid calling Overlaps where possible.
+// Rectangles are passed by pointer because this is the tree's hottest path.
+func boundsOverlaps(obj Object, bounds, r *Rectangle) (bool, bool) {
+	if bounds.xRange.min == bounds.xRange.max || bounds.yRange.min == bounds.yRange.max {
+		return false, false // empty
+	}
+
+	if bounds.xRange.max <= r.xRange.min || r.xRange.max <= bounds.xRange.min ||
+		bounds.yRange.max <= r.yRange.min || r.yRange.max <= bounds.yRange.min {
+		return false, false // disjoint
+	}
+
+	// A bound of math.MaxInt64 may have saturated: the object may reach a
+	// coordinate beyond any Limits, so only Overlaps can say whether it's
+	// contained.
+	if r.xRange.min <= bounds.xRange.min && bounds.xRange.max <= r.xRange.max &&
+		r.yRange.min <= bounds.yRange.min && bounds.yRange.max <= r.yRange.max &&
+		bounds.xRange.max != math.MaxInt64 && bounds.yRange.max != math.MaxInt64 {
+		return true, true // contained
+	}
+
+	return obj.Overlaps(*r)
+}
+
+// entry carries an Object through an insertion along with its bounding box
+// (if it's Bounded), so that Bounds is called once rather than at every node.
+type entry struct {
+	key     uint64
+	obj     Object
+	bounds  Rectangle
+	bounded bool
+}
+
+func newEntry(key uint64, obj Object) entry {
+	result := entry{key: key, obj: obj}
+	if b, ok := obj.(Bounded); ok {
+		result.bounds = b.Bounds()
+		result.bounded = true
+	}
+
+	return result
+}
+
+func (e *entry) overlaps(r *Rectangle) (bool, bool) {
+	if e.bounded {
+		return boundsOverlaps(e.obj, &e.bounds, r)
+	}
+
+	return e.obj.Overlaps(*r)
+}
@@ -0,0 +1,63 @@
+package tdqt
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// hSpan is a horizontal run of points (x1..x2 inclusive, at y) with a
+// bounding box built the way the objects package builds them: the exclusive
+// upper bounds saturate at math.MaxInt64.
+type hSpan struct {
+	x1, x2, y int64
+}
+
+func (s hSpan) Hash() uint64 { return uint64(s.x1) }
+
+func (s hSpan) Overlaps(r Rectangle) (bool, bool) {
+	if !r.yRange.Contains(s.y) {
+		return false, false
+	}
+	return s.x1 < r.xRange.max && s.x2 >= r.xRange.min, r.xRange.Contains(s.x1) && r.xRange.Contains(s.x2)
+}
+
+func (s hSpan) Bounds() Rectangle {
+	inc := func(i int64) int64 {
+		if i == math.MaxInt64 {
+			return i
+		}
+		return i + 1
+	}
+	return NewRectangle(NewLimits(s.x1, inc(s.x2)), NewLimits(s.y, inc(s.y)))
+}
+
+func TestObjectOverlaps_MaxInt64(t *testing.T) {
+	testCases := map[string]struct {
+		span hSpan
+		r    Rectangle
+	}{
+		"reaches_max_x":    {span: hSpan{x1: 10, x2: math.MaxInt64, y: 5}, r: NewRectangle(NewLimits(0, math.MaxInt64), NewLimits(0, 10))},
+		"short_of_max_x":   {span: hSpan{x1: 10, x2: math.MaxInt64 - 1, y: 5}, r: NewRectangle(NewLimits(0, math.MaxInt64), NewLimits(0, 10))},
+		"at_max_y":         {span: hSpan{x1: 10, x2: 20, y: math.MaxInt64}, r: NewRectangle(NewLimits(0, 100), NewLimits(0, math.MaxInt64))},
+		"reaches_max_both": {span: hSpan{x1: math.MaxInt64 - 1, x2: math.MaxInt64, y: math.MaxInt64 - 1}, r: NewRectangle(NewLimits(0, math.MaxInt64), NewLimits(0, math.MaxInt64))},
+		"inside":           {span: hSpan{x1: 10, x2: 20, y: 5}, r: NewRectangle(NewLimits(0, math.MaxInt64), NewLimits(0, math.MaxInt64))},
+	}
+
+	for tName, tCase := range testCases {
+		t.Run(tName, func(t *testing.T) {
+			t.Parallel()
+
+			expOverlap, expContained := tCase.span.Overlaps(tCase.r)
+			overlap, contained := objectOverlaps(tCase.span, tCase.r)
+			require.Equal(t, expOverlap, overlap)
+			require.Equal(t, expContained, contained)
+
+			e := newEntry(tCase.span.Hash(), tCase.span)
+			overlap, contained = e.overlaps(&tCase.r)
+			require.Equal(t, expOverlap, overlap)
+			require.Equal(t, expContained, contained)
+		})
+	}
+}
@@ -0,0 +1,319 @@
+package tdqt
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sync"
+)
+
+const (
+	// DefaultMaxObjects is the number of objects a node holds before it is
+	// subdivided, unless overridden with WithMaxObjects.
+	DefaultMaxObjects = 64
+
+	// DefaultMaxDepth is the depth at which nodes stop subdividing, unless
+	// overridden with WithMaxDepth. Halving an int64 axis 64 times reduces it
+	// to a single unit, so well-behaved splits never reach this depth.
+	DefaultMaxDepth = 64
+
+	// MaxDepthLimit is the largest depth which may be requested with
+	// WithMaxDepth. Depth is tracked as a uint8, and a node at this depth
+	// must still be able to hand objects to its subtrees.
+	MaxDepthLimit = math.MaxUint8 - 1
+)
+
+// CollisionPolicy determines what happens when an object is stored in a node
+// which already holds an equal object with the same Hash. Objects which
+// implement Equaler and aren't equal are distinct, and are stored side by
+// side regardless of the CollisionPolicy.
+type CollisionPolicy uint8
+
+const (
+	// CollisionReplace causes the newly inserted object to replace the
+	// stored object. The two are taken to be the same object, so an object
+	// which doesn't implement Equaler must only share its key with a stored
+	// object when their geometry matches too. Otherwise the replacement
+	// happens only in the nodes the new object reaches, and the tree's
+	// counts and summaries go stale. Use Identified objects to move things
+	// about.
+	CollisionReplace CollisionPolicy = iota
+
+	// CollisionKeep causes the stored object to be kept, and the newly
+	// inserted object to be discarded.
+	CollisionKeep
+)
+
+func (p CollisionPolicy) String() string {
+	switch p {
+	case CollisionReplace:
+		return "replace"
+	case CollisionKeep:
+		return "keep"
+	}
+	return fmt.Sprintf("CollisionPolicy(%d)", uint8(p))
+}
+
+// ConcurrencyMode determines whether a Tree guards itself against concurrent
+// use.
+type ConcurrencyMode uint8
+
+const (
+	// ConcurrencyNone performs no locking. Callers must not use the Tree from
+	// multiple goroutines concurrently.
+	ConcurrencyNone ConcurrencyMode = iota
+
+	// ConcurrencyLocked guards the Tree with a sync.RWMutex. Searches may run
+	// concurrently with one another, while Inserts are serialized. Callbacks
+	// run with the lock held, so they must not call back into the Tree's
+	// exported methods.
+	ConcurrencyLocked
+)
+
+func (m ConcurrencyMode) String() string {
+	switch m {
+	case ConcurrencyNone:
+		return "none"
+	case ConcurrencyLocked:
+		return "locked"
+	}
+	return fmt.Sprintf("ConcurrencyMode(%d)", uint8(m))
+}
+
+// PlacementMode determines where objects which span multiple subtrees are
+// stored.
+type PlacementMode uint8
+
+const (
+	// PlacementDuplicate stores objects only in leaves. An object which
+	// overlaps several subtrees is stored in each of them.
+	PlacementDuplicate PlacementMode = iota
+
+	// PlacementLoose stores each object exactly once, in the smallest node
+	// which fully contains it. Objects which would straddle the subtrees of
+	// an internal node are kept at that internal node.
+	PlacementLoose
+)
+
+func (m PlacementMode) String() string {
+	switch m {
+	case PlacementDuplicate:
+		return "duplicate"
+	case PlacementLoose:
+		return "loose"
+	}
+	return fmt.Sprintf("PlacementMode(%d)", uint8(m))
+}
+
+// HashFunc returns the key under which a Tree stores obj. Objects which
+// produce the same key are treated as the same object, unless they implement
+// Equaler.
+type HashFunc func(obj Object) uint64
+
+// Option configures a Tree. Options are passed to NewTree or
+// NewTreeWithOptions, and apply to the root node and to every subtree
+// created beneath it.
+type Option func(*config) error
+
+// config is shared (by pointer) between a Tree's root node and all of its
+// subtrees.
+type config struct {
+	maxObjects        uint16
+	maxDepth          uint8
+	minCellSize       uint64
+	midpointFunc      func(int64, int64) int64
+	splitStrategy     SplitStrategy
+	insertCallback    func(tree *Tree, depth uint8)
+	subdivideCallback func(tree *Tree, depth uint8)
+	collisionPolicy   CollisionPolicy
+	reverseIndex      bool
+	hashFunc          HashFunc
+	aggregators       []Aggregator
+	placement         PlacementMode
+	concurrency       ConcurrencyMode
+	mu                sync.RWMutex
+
+	// index maps the key of each Identified object (or of every object, with
+	// reverseIndex) to the nodes holding it. It's tree state rather than
+	// configuration, but like the mutex it must be shared by every node.
+	index map[uint64][]*Tree
+}
+
+func defaultConfig() *config {
+	return &config{
+		maxObjects:    DefaultMaxObjects,
+		maxDepth:      DefaultMaxDepth,
+		minCellSize:   1,
+		midpointFunc:  defaultMidpoint,
+		splitStrategy: MidpointSplit{},
+		hashFunc:      Object.Hash,
+		index:         make(map[uint64][]*Tree),
+	}
+}
+
+// WithMaxObjects sets the number of objects a node holds before it is
+// subdivided. It must be at least 1.
+func WithMaxObjects(n uint16) Option {
+	return func(c *config) error {
+		if n == 0 {
+			return errors.New("max objects must be at least 1")
+		}
+		c.maxObjects = n
+		return nil
+	}
+}
+
+// WithMaxDepth sets the depth at which nodes stop subdividing and become
+// overflow buckets, holding objects without regard for the max objects
+// setting. This bounds recursion when many objects crowd into one spot. The
+// default is DefaultMaxDepth. It must not exceed MaxDepthLimit.
+func WithMaxDepth(d uint8) Option {
+	return func(c *config) error {
+		if d > MaxDepthLimit {
+			return fmt.Errorf("max depth %d exceeds limit %d", d, MaxDepthLimit)
+		}
+		c.maxDepth = d
+		return nil
+	}
+}
+
+// WithMinCellSize sets the smallest width or height of a node. An axis is
+// not split if doing so would produce a cell narrower than n. The default is
+// 1.
+func WithMinCellSize(n uint64) Option {
+	return func(c *config) error {
+		if n == 0 {
+			return errors.New("min cell size must be at least 1")
+		}
+		c.minCellSize = n
+		return nil
+	}
+}
+
+// WithMidpointFunc sets the function used to find the midpoint of a node's
+// range (min, max). It is used by MidpointSplit, the default SplitStrategy.
+func WithMidpointFunc(f func(min, max int64) int64) Option {
+	return func(c *config) error {
+		if f == nil {
+			return errors.New("midpoint func must not be nil")
+		}
+		c.midpointFunc = f
+		return nil
+	}
+}
+
+// WithSplitStrategy sets the SplitStrategy used to choose where nodes are
+// subdivided. The default is MidpointSplit.
+func WithSplitStrategy(s SplitStrategy) Option {
+	return func(c *config) error {
+		if s == nil {
+			return errors.New("split strategy must not be nil")
+		}
+		c.splitStrategy = s
+		return nil
+	}
+}
+
+// WithInsertCallback sets a function to be called each time an object is
+// stored in a node, including when objects are redistributed during
+// subdivision.
+func WithInsertCallback(f func(tree *Tree, depth uint8)) Option {
+	return func(c *config) error {
+		c.insertCallback = f
+		return nil
+	}
+}
+
+// WithSubdivideCallback sets a function to be called each time a node is
+// subdivided, before its objects are redistributed.
+func WithSubdivideCallback(f func(tree *Tree, depth uint8)) Option {
+	return func(c *config) error {
+		c.subdivideCallback = f
+		return nil
+	}
+}
+
+// WithCollisionPolicy sets the policy applied when a node already holds an
+// object with the same Hash as, and equal to (see Equaler), an object being
+// stored. The default is CollisionReplace.
+func WithCollisionPolicy(p CollisionPolicy) Option {
+	return func(c *config) error {
+		switch p {
+		case CollisionReplace, CollisionKeep:
+		default:
+			return fmt.Errorf("unknown collision policy %s", p)
+		}
+		c.collisionPolicy = p
+		return nil
+	}
+}
+
+// WithHashFunc sets the function used to key objects. The default keys each
+// object by its own Hash method. Supplying a seeded hash (see
+// objects.HashWith) keeps adversarial inputs from forcing collisions.
+func WithHashFunc(f HashFunc) Option {
+	return func(c *config) error {
+		if f == nil {
+			return errors.New("hash func must not be nil")
+		}
+		c.hashFunc = f
+		return nil
+	}
+}
+
+// WithReverseIndex causes the tree to index the nodes holding every object,
+// not just Identified ones. This costs memory and some insert time, but
+// allows any object to be found with Locate, and removed with Remove, by its
+// key (see Tree.Key).
+func WithReverseIndex() Option {
+	return func(c *config) error {
+		c.reverseIndex = true
+		return nil
+	}
+}
+
+// WithAggregator causes every node to maintain a Summary of its objects, so
+// that Aggregate can answer queries using agg without visiting every object.
+// It may be supplied more than once. agg must be comparable (a pointer, or a
+// struct without slices, maps or funcs), as Aggregate recognizes it with ==.
+func WithAggregator(agg Aggregator) Option {
+	return func(c *config) error {
+		if agg == nil {
+			return errors.New("aggregator must not be nil")
+		}
+		if !reflect.TypeOf(agg).Comparable() {
+			return fmt.Errorf("aggregator type %T is not comparable", agg)
+		}
+		c.aggregators = append(c.aggregators, agg)
+		return nil
+	}
+}
+
+// WithPlacement sets the Tree's PlacementMode. The default is
+// PlacementDuplicate.
+func WithPlacement(m PlacementMode) Option {
+	return func(c *config) error {
+		switch m {
+		case PlacementDuplicate, PlacementLoose:
+		default:
+			return fmt.Errorf("unknown placement mode %s", m)
+		}
+		c.placement = m
+		return nil
+	}
+}
+
+// WithConcurrency sets the Tree's ConcurrencyMode. The default is
+// ConcurrencyNone.
+func WithConcurrency(m ConcurrencyMode) Option {
+	return func(c *config) error {
+		switch m {
+		case ConcurrencyNone, ConcurrencyLocked:
+		default:
+			return fmt.Errorf("unknown concurrency mode %s", m)
+		}
+		c.concurrency = m
+		return nil
+	}
+}
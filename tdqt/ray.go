@@ -0,0 +1,243 @@
+package tdqt
+
+import (
+	"container/heap"
+	"iter"
+	"math"
+	"slices"
+
+	"github.com/chrismarget/two-dimensional-quad-tree/internal/exact"
+)
+
+// RayIntersector is an optional interface which may be implemented by an
+// Object to take part in Tree.Raycast. Objects which don't implement it are
+// invisible to rays.
+type RayIntersector interface {
+	// IntersectRay reports whether the ray from (x,y) in the direction
+	// (dx,dy) meets the object and, if so, the distance from (x,y) to the
+	// nearest point at which it does. A ray which starts on the object meets
+	// it at distance 0. (dx,dy) is never (0,0).
+	IntersectRay(x, y, dx, dy int64) (float64, bool)
+}
+
+// Raycast yields the objects hit by the ray from (x,y) in the direction
+// (dx,dy), nearest first, along with the distance to each hit. Hits further
+// than maxDist are ignored. Only objects implementing RayIntersector can be
+// hit, and each is yielded once. Stop the iteration after the first hit for
+// line-of-sight or picking queries: nodes further along the ray are visited
+// only as the iteration proceeds.
+//
+// Nodes are visited in the order the ray passes through them, and the ray is
+// only tested against objects stored in those nodes. Which nodes the ray
+// passes through is decided exactly, so no hit is missed however large the
+// coordinates. Where an object extends beyond the tree's area, hits on the
+// part outside it may be missed or yielded out of order. A zero direction
+// yields nothing.
+//
+// The tree is read-locked (when ConcurrencyLocked) for the duration of the
+// iteration.
+func (t *Tree) Raycast(x, y, dx, dy int64, maxDist float64) iter.Seq2[Object, float64] {
+	return func(yield func(Object, float64) bool) {
+		if dx == 0 && dy == 0 {
+			return
+		}
+
+		defer t.rLock()()
+
+		rc := raycaster{
+			x: x, y: y, dx: dx, dy: dy,
+			length:  math.Hypot(float64(dx), float64(dy)),
+			maxDist: maxDist,
+			yield:   yield,
+		}
+
+		// Objects stored in several leaves would be tested more than once.
+		if t.cfg.placement == PlacementDuplicate {
+			rc.tested = make(map[uint64][]Object)
+		}
+
+		if !rc.crosses(t.area) {
+			return
+		}
+		if _, exit := rc.span(t.area); rc.walk(t, exit) {
+			rc.flush(math.Inf(1))
+		}
+	}
+}
+
+type raycaster struct {
+	x, y, dx, dy int64
+	length       float64 // of (dx,dy)
+
+	maxDist float64
+	yield   func(Object, float64) bool
+
+	// hits holds the hits found in the nodes visited so far, which can't be
+	// yielded until it's certain that no nearer hit lies in a later node.
+	hits rayHits
+
+	// tested holds the objects already tested which may be stored in several
+	// leaves, by key. It's nil in loose mode, where each object is stored
+	// only once.
+	tested map[uint64][]Object
+}
+
+// crosses reports whether the ray passes through r. The test is exact.
+func (rc *raycaster) crosses(r Rectangle) bool {
+	return exact.RayOverlaps(rc.x, rc.y, rc.dx, rc.dy, r.box())
+}
+
+// span returns the distances along the ray at which it enters and leaves r,
+// which it must cross, limited to [0, maxDist]. The distances are rounded,
+// and serve only to order the nodes and to decide when hits may be yielded:
+// they're computed from exact coordinate differences, so the rounding is
+// slight even far from the origin.
+func (rc *raycaster) span(r Rectangle) (float64, float64) {
+	enter, exit := 0.0, rc.maxDist
+
+	axes := [2]struct {
+		o, d   int64
+		lo, hi int64
+	}{
+		{rc.x, rc.dx, r.xRange.min, r.xRange.max},
+		{rc.y, rc.dy, r.yRange.min, r.yRange.max},
+	}
+	for _, a := range axes {
+		if a.d == 0 {
+			continue // the ray runs within [lo, hi) along this axis
+		}
+
+		t1, t2 := rc.distance(a.lo, a.o, a.d), rc.distance(a.hi, a.o, a.d)
+		if t1 > t2 {
+			t1, t2 = t2, t1
+		}
+		enter, exit = max(enter, t1), min(exit, t2)
+	}
+
+	return enter, exit
+}
+
+// distance returns the distance along the ray at which the coordinate whose
+// origin is o and direction d reaches v.
+func (rc *raycaster) distance(v, o, d int64) float64 {
+	neg, diff := exact.Difference(v, o)
+	result := float64(diff) / math.Abs(float64(d)) * rc.length
+	if neg != (d < 0) {
+		return -result
+	}
+
+	return result
+}
+
+// walk tests the objects stored at n and in the subtrees which the ray passes
+// through, nearest subtree first. exit is the distance at which the ray leaves
+// n. It returns false when the caller has stopped the iteration.
+func (rc *raycaster) walk(n *Tree, exit float64) bool {
+	rc.test(n)
+
+	if n.subTrees[0] == nil {
+		// Every node the ray passes through before leaving this leaf has
+		// been visited, so no hit yet to be found can be nearer than exit.
+		return rc.flush(exit)
+	}
+
+	type crossing struct {
+		n           *Tree
+		enter, exit float64
+	}
+	var crossings []crossing
+	for _, st := range n.subTrees {
+		if st == nil {
+			break
+		}
+		if !rc.crosses(st.area) {
+			continue
+		}
+		if enter, exit := rc.span(st.area); enter <= rc.maxDist {
+			crossings = append(crossings, crossing{n: st, enter: enter, exit: exit})
+		}
+	}
+	slices.SortFunc(crossings, func(a, b crossing) int {
+		switch {
+		case a.enter < b.enter:
+			return -1
+		case a.enter > b.enter:
+			return 1
+		}
+		return 0
+	})
+
+	for _, c := range crossings {
+		if !rc.walk(c.n, c.exit) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// test tests the ray against the objects stored at n, saving any hits.
+func (rc *raycaster) test(n *Tree) {
+	for _, o := range n.held() {
+		ri, ok := o.obj.(RayIntersector)
+		if !ok || !rc.first(o) {
+			continue
+		}
+
+		if dist, hit := ri.IntersectRay(rc.x, rc.y, rc.dx, rc.dy); hit && dist <= rc.maxDist {
+			heap.Push(&rc.hits, rayHit{obj: o.obj, dist: dist})
+		}
+	}
+}
+
+// first reports whether o is being tested for the first time. Objects which
+// lie wholly within the node they were found at are stored only there, and
+// needn't be remembered.
+func (rc *raycaster) first(o keyedObject) bool {
+	if rc.tested == nil || !o.spans {
+		return true
+	}
+
+	for _, t := range rc.tested[o.key] {
+		if equal(t, o.obj) {
+			return false
+		}
+	}
+	rc.tested[o.key] = append(rc.tested[o.key], o.obj)
+
+	return true
+}
+
+// flush yields the saved hits no further than dist, nearest first. It returns
+// false when the caller has stopped the iteration.
+func (rc *raycaster) flush(dist float64) bool {
+	for len(rc.hits) > 0 && rc.hits[0].dist <= dist {
+		h := heap.Pop(&rc.hits).(rayHit)
+		if !rc.yield(h.obj, h.dist) {
+			return false
+		}
+	}
+
+	return true
+}
+
+type rayHit struct {
+	obj  Object
+	dist float64
+}
+
+// rayHits is a min-heap of hits, ordered by distance.
+type rayHits []rayHit
+
+func (h rayHits) Len() int           { return len(h) }
+func (h rayHits) Less(i, j int) bool { return h[i].dist < h[j].dist }
+func (h rayHits) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *rayHits) Push(x any)        { *h = append(*h, x.(rayHit)) }
+
+func (h *rayHits) Pop() any {
+	old := *h
+	result := old[len(old)-1]
+	*h = old[:len(old)-1]
+
+	return result
+}
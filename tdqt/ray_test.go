@@ -0,0 +1,140 @@
+package tdqt_test
+
+import (
+	"cmp"
+	"image/color"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/chrismarget/two-dimensional-quad-tree/objects"
+	"github.com/chrismarget/two-dimensional-quad-tree/tdqt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTree_Raycast(t *testing.T) {
+	everywhere := tdqt.NewRectangle(tdqt.NewLimits(0, 1000), tdqt.NewLimits(0, 1000))
+	rng := rand.New(rand.NewPCG(7, 8))
+
+	var lines []objects.ColorLine
+	for range 300 {
+		x, y := rng.Int64N(1000), rng.Int64N(1000)
+		lines = append(lines, objects.NewColorLine(x, y, min(999, x+rng.Int64N(200)), min(999, y+rng.Int64N(50)), color.RGBA{}))
+	}
+
+	type hit struct {
+		hash uint64
+		dist float64
+	}
+
+	for _, placement := range []tdqt.PlacementMode{tdqt.PlacementDuplicate, tdqt.PlacementLoose} {
+		t.Run(placement.String(), func(t *testing.T) {
+			tree := tdqt.NewTree(everywhere, tdqt.WithMaxObjects(4), tdqt.WithPlacement(placement))
+			for _, l := range lines {
+				tree.Insert(l)
+			}
+			// Points can't be hit by rays.
+			tree.Insert(objects.NewColorPoint(500, 500, color.RGBA{}))
+
+			var hits int
+			for range 100 {
+				x, y := rng.Int64N(1000), rng.Int64N(1000)
+				dx, dy := rng.Int64N(201)-100, rng.Int64N(201)-100
+				maxDist := float64(rng.Int64N(1500))
+
+				var expected []hit
+				for _, l := range lines {
+					if dist, ok := l.IntersectRay(x, y, dx, dy); ok && dist <= maxDist && (dx != 0 || dy != 0) {
+						expected = append(expected, hit{hash: l.Hash(), dist: dist})
+					}
+				}
+				slices.SortFunc(expected, func(a, b hit) int { return cmp.Compare(a.dist, b.dist) })
+
+				var got []hit
+				for obj, dist := range tree.Raycast(x, y, dx, dy, maxDist) {
+					got = append(got, hit{hash: obj.Hash(), dist: dist})
+				}
+
+				// Hits come nearest first, and the set matches. (Ties may
+				// come in either order.)
+				require.True(t, slices.IsSortedFunc(got, func(a, b hit) int { return cmp.Compare(a.dist, b.dist) }))
+				require.ElementsMatch(t, expected, got)
+				hits += len(got)
+
+				// The first hit alone.
+				for obj, dist := range tree.Raycast(x, y, dx, dy, maxDist) {
+					require.Equal(t, expected[0].dist, dist)
+					_, ok := obj.(objects.ColorLine)
+					require.True(t, ok)
+					break
+				}
+			}
+			require.Greater(t, hits, 100)
+		})
+	}
+}
+
+func TestTree_Raycast_LargeCoordinates(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		placement tdqt.PlacementMode
+		seed      uint64
+	}
+
+	testCases := map[string]testCase{
+		"duplicate": {placement: tdqt.PlacementDuplicate, seed: 1},
+		"loose":     {placement: tdqt.PlacementLoose, seed: 2},
+	}
+
+	// Short lines clustered far from the origin, where float64 can't
+	// represent neighboring coordinates.
+	const base = 1 << 62
+	plane := tdqt.NewRectangle(tdqt.NewLimits(math.MinInt64, math.MaxInt64), tdqt.NewLimits(math.MinInt64, math.MaxInt64))
+
+	for tName, tCase := range testCases {
+		t.Run(tName, func(t *testing.T) {
+			t.Parallel()
+
+			rng := rand.New(rand.NewPCG(tCase.seed, tCase.seed))
+			tree := tdqt.NewTree(plane, tdqt.WithMaxObjects(2), tdqt.WithPlacement(tCase.placement))
+
+			var lines []objects.ColorLine
+			for range 3000 {
+				x, y := base+rng.Int64N(10_000), base+rng.Int64N(10_000)
+				l := objects.NewColorLine(x, y, x+rng.Int64N(201)-100, y+rng.Int64N(201)-100, color.RGBA{})
+				lines = append(lines, l)
+				tree.Insert(l)
+			}
+
+			var hits int
+			for range 200 {
+				x, y := base+rng.Int64N(10_000), base+rng.Int64N(10_000)
+				dx, dy := rng.Int64N(2001)-1000, rng.Int64N(2001)-1000
+				if dx == 0 && dy == 0 {
+					continue
+				}
+
+				var expected []uint64
+				for _, l := range lines {
+					if _, ok := l.IntersectRay(x, y, dx, dy); ok {
+						expected = append(expected, l.Hash())
+					}
+				}
+
+				var got []uint64
+				var dists []float64
+				for obj, dist := range tree.Raycast(x, y, dx, dy, math.Inf(1)) {
+					got = append(got, obj.Hash())
+					dists = append(dists, dist)
+				}
+
+				require.ElementsMatch(t, expected, got)
+				require.True(t, slices.IsSorted(dists))
+				hits += len(got)
+			}
+			require.Greater(t, hits, 200)
+		})
+	}
+}
@@ -1,6 +1,11 @@
 package tdqt
 
-import "fmt"
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/chrismarget/two-dimensional-quad-tree/internal/exact"
+)
 
 type Rectangle struct {
 	xRange Limits
@@ -18,18 +23,206 @@ func (r Rectangle) Limits() (Limits, Limits) {
 	return r.xRange, r.yRange
 }
 
-func (r Rectangle) cannotSubdivide() bool {
-	return r.xRange.cannotSubdivide() && r.yRange.cannotSubdivide()
+func (r Rectangle) empty() bool {
+	return r.xRange.min == r.xRange.max || r.yRange.min == r.yRange.max
+}
+
+func (r Rectangle) cannotSubdivide(minSize uint64) bool {
+	return r.xRange.cannotSubdivide(minSize) && r.yRange.cannotSubdivide(minSize)
 }
 
 func (r Rectangle) Overlaps(b Rectangle) bool {
-	return r.xRange.overlaps(b.xRange) && r.yRange.overlaps(b.yRange)
+	return r.xRange.Overlaps(b.xRange) && r.yRange.Overlaps(b.yRange)
+}
+
+// Intersection returns the Rectangle covered by both r and b. The second
+// return value is false (and the Rectangle is the zero value) when r and b
+// don't overlap.
+func (r Rectangle) Intersection(b Rectangle) (Rectangle, bool) {
+	result := Rectangle{
+		xRange: Limits{
+			min:          max(r.xRange.min, b.xRange.min),
+			max:          min(r.xRange.max, b.xRange.max),
+			midpointFunc: r.xRange.midpointFunc,
+		},
+		yRange: Limits{
+			min:          max(r.yRange.min, b.yRange.min),
+			max:          min(r.yRange.max, b.yRange.max),
+			midpointFunc: r.yRange.midpointFunc,
+		},
+	}
+
+	if result.xRange.min >= result.xRange.max || result.yRange.min >= result.yRange.max {
+		return Rectangle{}, false
+	}
+
+	return result, true
+}
+
+// Union returns the smallest Rectangle which contains both r and b. Empty
+// Rectangles contribute nothing to the result.
+func (r Rectangle) Union(b Rectangle) Rectangle {
+	switch {
+	case b.empty():
+		return r
+	case r.empty():
+		return b
+	}
+
+	return Rectangle{
+		xRange: Limits{
+			min:          min(r.xRange.min, b.xRange.min),
+			max:          max(r.xRange.max, b.xRange.max),
+			midpointFunc: r.xRange.midpointFunc,
+		},
+		yRange: Limits{
+			min:          min(r.yRange.min, b.yRange.min),
+			max:          max(r.yRange.max, b.yRange.max),
+			midpointFunc: r.yRange.midpointFunc,
+		},
+	}
+}
+
+// Equal indicates whether r and b have the same limits. The midpoint
+// functions of the limits aren't compared.
+func (r Rectangle) Equal(b Rectangle) bool {
+	return r.xRange.min == b.xRange.min && r.xRange.max == b.xRange.max &&
+		r.yRange.min == b.yRange.min && r.yRange.max == b.yRange.max
+}
+
+// ContainsRect indicates whether b lies entirely within r.
+func (r Rectangle) ContainsRect(b Rectangle) bool {
+	return r.xRange.min <= b.xRange.min && b.xRange.max <= r.xRange.max &&
+		r.yRange.min <= b.yRange.min && b.yRange.max <= r.yRange.max
+}
+
+// ContainsPoint indicates whether the point (x,y) lies within r.
+func (r Rectangle) ContainsPoint(x, y int64) bool {
+	return r.xRange.Contains(x) && r.yRange.Contains(y)
+}
+
+// Area returns the number of unit cells covered by r. The result may need as
+// many as 128 bits, so it is returned as a big.Int.
+func (r Rectangle) Area() *big.Int {
+	x := new(big.Int).SetUint64(r.xRange.width())
+	y := new(big.Int).SetUint64(r.yRange.width())
+
+	return x.Mul(x, y)
+}
+
+// Center returns the coordinates of the center of r, rounded down.
+func (r Rectangle) Center() (int64, int64) {
+	return floorMidpoint(r.xRange.min, r.xRange.max), floorMidpoint(r.yRange.min, r.yRange.max)
+}
+
+// Expand returns a copy of r grown by d in every direction. Negative values of
+// d shrink the Rectangle; a Rectangle shrunk past nothing collapses to an
+// empty Rectangle at its center. Results saturate at the limits of int64.
+func (r Rectangle) Expand(d int64) Rectangle {
+	return Rectangle{
+		xRange: r.xRange.expand(d),
+		yRange: r.yRange.expand(d),
+	}
+}
+
+// Cells divides r into a grid of cols by rows cells, indexed [row][col].
+// Row 0 lies along the bottom of r (its minimum y) and column 0 along the
+// left. Cell widths and heights differ by at most one; where r is narrower
+// than the grid, some cells are empty. The result is nil unless cols and rows
+// are both positive.
+func (r Rectangle) Cells(cols, rows int) [][]Rectangle {
+	if cols <= 0 || rows <= 0 {
+		return nil
+	}
+
+	xs, ys := r.xRange.divide(cols), r.yRange.divide(rows)
+
+	result := make([][]Rectangle, rows)
+	for i := range result {
+		result[i] = make([]Rectangle, cols)
+		for j := range result[i] {
+			result[i][j] = Rectangle{xs[j], ys[i]}
+		}
+	}
+
+	return result
+}
+
+// Quadrants divides r at the midpoint of each axis. The quadrants are
+// returned in the order I, II, III, IV (counterclockwise, beginning at the
+// top right). When one axis is too narrow to split, the two halves of the
+// other axis are returned instead: top then bottom, or right then left. When
+// neither axis can be split, the result is nil.
+func (r Rectangle) Quadrants() []Rectangle {
+	return r.quadrants(r.xRange.midpoint(), r.yRange.midpoint(), !r.xRange.cannotSubdivide(1), !r.yRange.cannotSubdivide(1))
+}
+
+// quadrants divides r at (xMid, yMid). Axes for which the split boolean is
+// false are not divided. See Quadrants for the ordering of the results.
+func (r Rectangle) quadrants(xMid, yMid int64, splitX, splitY bool) []Rectangle {
+	xMin, xMax, yMin, yMax := r.xyMinMax()
+	xf := r.xRange.midpointFunc
+	yf := r.yRange.midpointFunc
+
+	left, right := Limits{xMin, xMid, xf}, Limits{xMid, xMax, xf}
+	bottom, top := Limits{yMin, yMid, yf}, Limits{yMid, yMax, yf}
+
+	switch {
+	case splitX && splitY:
+		return []Rectangle{
+			{right, top},    // quadrant I
+			{left, top},     // quadrant II
+			{left, bottom},  // quadrant III
+			{right, bottom}, // quadrant IV
+		}
+	case splitY:
+		return []Rectangle{
+			{r.xRange, top},    // quadrant I and II
+			{r.xRange, bottom}, // quadrant III and IV
+		}
+	case splitX:
+		return []Rectangle{
+			{right, r.yRange}, // quadrant I and IV
+			{left, r.yRange},  // quadrant II and III
+		}
+	}
+
+	return nil
 }
 
 func (r Rectangle) xyMinMax() (int64, int64, int64, int64) {
 	return r.xRange.min, r.xRange.max, r.yRange.min, r.yRange.max
 }
 
+// box returns the exact.Box covering the same points as r.
+func (r Rectangle) box() exact.Box {
+	return exact.Box{
+		XMin: r.xRange.min, XMax: r.xRange.max,
+		YMin: r.yRange.min, YMax: r.yRange.max,
+	}
+}
+
+// NewRectangleE returns a Rectangle bounded by the x and y Limits. An error is
+// returned if either of the Limits is inverted.
+func NewRectangleE(x, y Limits) (Rectangle, error) {
+	if err := x.valid(); err != nil {
+		return Rectangle{}, fmt.Errorf("x-axis: %w", err)
+	}
+
+	if err := y.valid(); err != nil {
+		return Rectangle{}, fmt.Errorf("y-axis: %w", err)
+	}
+
+	return Rectangle{x, y}, nil
+}
+
+// NewRectangle is a convenience wrapper around NewRectangleE which panics
+// rather than returning an error.
 func NewRectangle(x, y Limits) Rectangle {
-	return Rectangle{x, y}
+	result, err := NewRectangleE(x, y)
+	if err != nil {
+		panic(err)
+	}
+
+	return result
 }
@@ -0,0 +1,258 @@
+package tdqt
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func rect(xMin, xMax, yMin, yMax int64) Rectangle {
+	return NewRectangle(NewLimits(xMin, xMax), NewLimits(yMin, yMax))
+}
+
+func requireSameRectangle(t *testing.T, expected, actual Rectangle) {
+	t.Helper()
+
+	eXMin, eXMax, eYMin, eYMax := expected.xyMinMax()
+	aXMin, aXMax, aYMin, aYMax := actual.xyMinMax()
+	require.Equalf(t, [4]int64{eXMin, eXMax, eYMin, eYMax}, [4]int64{aXMin, aXMax, aYMin, aYMax},
+		"expected %s, got %s", expected, actual)
+}
+
+func TestRectangle_Intersection(t *testing.T) {
+	type testCase struct {
+		a, b     Rectangle
+		expected Rectangle
+		ok       bool
+	}
+
+	testCases := map[string]testCase{
+		"partial":   {a: rect(0, 10, 0, 10), b: rect(5, 15, -5, 5), expected: rect(5, 10, 0, 5), ok: true},
+		"contained": {a: rect(0, 10, 0, 10), b: rect(2, 3, 4, 5), expected: rect(2, 3, 4, 5), ok: true},
+		"touching":  {a: rect(0, 10, 0, 10), b: rect(10, 20, 0, 10)},
+		"disjoint":  {a: rect(0, 10, 0, 10), b: rect(20, 30, 20, 30)},
+		"full": {
+			a:        rect(math.MinInt64, math.MaxInt64, math.MinInt64, math.MaxInt64),
+			b:        rect(math.MinInt64, 0, 0, math.MaxInt64),
+			expected: rect(math.MinInt64, 0, 0, math.MaxInt64),
+			ok:       true,
+		},
+	}
+
+	for tName, tCase := range testCases {
+		t.Run(tName, func(t *testing.T) {
+			t.Parallel()
+
+			for _, pair := range [][2]Rectangle{{tCase.a, tCase.b}, {tCase.b, tCase.a}} {
+				actual, ok := pair[0].Intersection(pair[1])
+				require.Equal(t, tCase.ok, ok)
+				if ok {
+					requireSameRectangle(t, tCase.expected, actual)
+				}
+			}
+		})
+	}
+}
+
+func TestRectangle_Union(t *testing.T) {
+	requireSameRectangle(t, rect(-5, 10, 0, 30), rect(0, 10, 0, 10).Union(rect(-5, 0, 20, 30)))
+	requireSameRectangle(t, rect(0, 10, 0, 10), rect(0, 10, 0, 10).Union(rect(100, 100, 0, 5)))
+	requireSameRectangle(t, rect(0, 10, 0, 10), rect(100, 100, 0, 5).Union(rect(0, 10, 0, 10)))
+}
+
+func TestRectangle_Contains(t *testing.T) {
+	r := rect(0, 10, 0, 10)
+
+	require.True(t, r.ContainsRect(r))
+	require.True(t, r.ContainsRect(rect(2, 3, 4, 5)))
+	require.False(t, r.ContainsRect(rect(2, 11, 4, 5)))
+	require.False(t, r.ContainsRect(rect(-1, 3, 4, 5)))
+
+	require.True(t, r.ContainsPoint(0, 0))
+	require.True(t, r.ContainsPoint(9, 9))
+	require.False(t, r.ContainsPoint(10, 9))
+	require.False(t, r.ContainsPoint(9, 10))
+	require.False(t, r.ContainsPoint(-1, 0))
+}
+
+func TestRectangle_Area(t *testing.T) {
+	require.Zero(t, big.NewInt(50).Cmp(rect(0, 10, -5, 0).Area()))
+	require.Zero(t, rect(0, 10, 5, 5).Area().Sign())
+
+	// (2^64 - 1)^2 overflows any fixed-size Go integer
+	full := new(big.Int).SetUint64(math.MaxUint64)
+	full.Mul(full, full)
+	require.Zero(t, full.Cmp(rect(math.MinInt64, math.MaxInt64, math.MinInt64, math.MaxInt64).Area()))
+}
+
+func TestRectangle_Center(t *testing.T) {
+	type testCase struct {
+		r    Rectangle
+		x, y int64
+	}
+
+	testCases := map[string]testCase{
+		"even":     {r: rect(0, 10, -10, 0), x: 5, y: -5},
+		"odd":      {r: rect(0, 5, -5, 0), x: 2, y: -3},
+		"full":     {r: rect(math.MinInt64, math.MaxInt64, math.MinInt64, math.MaxInt64), x: -1, y: -1},
+		"positive": {r: rect(0, math.MaxInt64, math.MaxInt64-2, math.MaxInt64), x: math.MaxInt64 / 2, y: math.MaxInt64 - 1},
+	}
+
+	for tName, tCase := range testCases {
+		t.Run(tName, func(t *testing.T) {
+			t.Parallel()
+
+			x, y := tCase.r.Center()
+			require.Equal(t, tCase.x, x)
+			require.Equal(t, tCase.y, y)
+		})
+	}
+}
+
+func TestRectangle_Expand(t *testing.T) {
+	type testCase struct {
+		r        Rectangle
+		d        int64
+		expected Rectangle
+	}
+
+	testCases := map[string]testCase{
+		"grow":      {r: rect(0, 10, 0, 10), d: 5, expected: rect(-5, 15, -5, 15)},
+		"shrink":    {r: rect(0, 10, 0, 20), d: -3, expected: rect(3, 7, 3, 17)},
+		"collapse":  {r: rect(0, 10, 0, 20), d: -5, expected: rect(5, 5, 5, 15)},
+		"overdo":    {r: rect(0, 10, 0, 20), d: -50, expected: rect(5, 5, 10, 10)},
+		"saturate":  {r: rect(-10, 10, -10, 10), d: math.MaxInt64, expected: rect(math.MinInt64, math.MaxInt64, math.MinInt64, math.MaxInt64)},
+		"min_int64": {r: rect(-10, 10, -10, 10), d: math.MinInt64, expected: rect(0, 0, 0, 0)},
+	}
+
+	for tName, tCase := range testCases {
+		t.Run(tName, func(t *testing.T) {
+			t.Parallel()
+
+			requireSameRectangle(t, tCase.expected, tCase.r.Expand(tCase.d))
+		})
+	}
+}
+
+func TestRectangle_Quadrants(t *testing.T) {
+	type testCase struct {
+		r        Rectangle
+		expected []Rectangle
+	}
+
+	testCases := map[string]testCase{
+		"quadrants": {
+			r: rect(0, 10, 0, 20),
+			expected: []Rectangle{
+				rect(5, 10, 10, 20),
+				rect(0, 5, 10, 20),
+				rect(0, 5, 0, 10),
+				rect(5, 10, 0, 10),
+			},
+		},
+		"narrow_x": {
+			r: rect(0, 1, 0, 20),
+			expected: []Rectangle{
+				rect(0, 1, 10, 20),
+				rect(0, 1, 0, 10),
+			},
+		},
+		"narrow_y": {
+			r: rect(0, 10, 7, 8),
+			expected: []Rectangle{
+				rect(5, 10, 7, 8),
+				rect(0, 5, 7, 8),
+			},
+		},
+		"unit": {
+			r: rect(0, 1, 0, 1),
+		},
+		"zero_value": {
+			r: Rectangle{},
+		},
+		"no_midpoint_func": {
+			r: Rectangle{xRange: Limits{min: 0, max: 10}, yRange: Limits{min: 0, max: 20}},
+			expected: []Rectangle{
+				rect(5, 10, 10, 20),
+				rect(0, 5, 10, 20),
+				rect(0, 5, 0, 10),
+				rect(5, 10, 0, 10),
+			},
+		},
+	}
+
+	for tName, tCase := range testCases {
+		t.Run(tName, func(t *testing.T) {
+			t.Parallel()
+
+			actual := tCase.r.Quadrants()
+			require.Len(t, actual, len(tCase.expected))
+			for i := range actual {
+				requireSameRectangle(t, tCase.expected[i], actual[i])
+			}
+		})
+	}
+}
+
+func TestRectangle_Cells(t *testing.T) {
+	type testCase struct {
+		r          Rectangle
+		cols, rows int
+		expected   [][]Rectangle
+	}
+
+	testCases := map[string]testCase{
+		"even": {
+			r:    rect(0, 10, 0, 20),
+			cols: 2, rows: 2,
+			expected: [][]Rectangle{
+				{rect(0, 5, 0, 10), rect(5, 10, 0, 10)},
+				{rect(0, 5, 10, 20), rect(5, 10, 10, 20)},
+			},
+		},
+		"uneven": {
+			r:    rect(-5, 5, 0, 1),
+			cols: 3, rows: 1,
+			expected: [][]Rectangle{
+				{rect(-5, -2, 0, 1), rect(-2, 1, 0, 1), rect(1, 5, 0, 1)},
+			},
+		},
+		"narrow": {
+			r:    rect(0, 1, 0, 2),
+			cols: 1, rows: 3,
+			expected: [][]Rectangle{
+				{rect(0, 1, 0, 0)},
+				{rect(0, 1, 0, 1)},
+				{rect(0, 1, 1, 2)},
+			},
+		},
+		"huge": {
+			r:    rect(math.MinInt64, math.MaxInt64, 0, 1),
+			cols: 2, rows: 1,
+			expected: [][]Rectangle{
+				{rect(math.MinInt64, -1, 0, 1), rect(-1, math.MaxInt64, 0, 1)},
+			},
+		},
+		"no_cols": {
+			r:    rect(0, 10, 0, 10),
+			cols: 0, rows: 2,
+		},
+	}
+
+	for tName, tCase := range testCases {
+		t.Run(tName, func(t *testing.T) {
+			t.Parallel()
+
+			actual := tCase.r.Cells(tCase.cols, tCase.rows)
+			require.Len(t, actual, len(tCase.expected))
+			for i := range actual {
+				require.Len(t, actual[i], len(tCase.expected[i]))
+				for j := range actual[i] {
+					requireSameRectangle(t, tCase.expected[i][j], actual[i][j])
+				}
+			}
+		})
+	}
+}
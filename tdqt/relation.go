@@ -0,0 +1,58 @@
+package tdqt
+
+import "fmt"
+
+// Relation is one of the thirteen interval relations described by James F.
+// Allen, identifying how one (non-empty) Limits is positioned relative to
+// another. Limits are half-open, so "meets" means that one range ends at the
+// (exclusive) max where the other begins.
+type Relation uint8
+
+const (
+	RelationUndefined    Relation = iota // one or both ranges are empty
+	RelationBefore                       // l ends before b begins, with a gap
+	RelationMeets                        // l ends exactly where b begins
+	RelationOverlaps                     // l begins first, b ends last, they share some values
+	RelationStarts                       // l and b begin together, l ends first
+	RelationDuring                       // l lies strictly inside b
+	RelationFinishes                     // l begins after b, they end together
+	RelationEqual                        // l and b are the same range
+	RelationFinishedBy                   // inverse of RelationFinishes
+	RelationContains                     // inverse of RelationDuring
+	RelationStartedBy                    // inverse of RelationStarts
+	RelationOverlappedBy                 // inverse of RelationOverlaps
+	RelationMetBy                        // inverse of RelationMeets
+	RelationAfter                        // inverse of RelationBefore
+)
+
+var relationNames = [...]string{
+	RelationUndefined:    "undefined",
+	RelationBefore:       "before",
+	RelationMeets:        "meets",
+	RelationOverlaps:     "overlaps",
+	RelationStarts:       "starts",
+	RelationDuring:       "during",
+	RelationFinishes:     "finishes",
+	RelationEqual:        "equal",
+	RelationFinishedBy:   "finished by",
+	RelationContains:     "contains",
+	RelationStartedBy:    "started by",
+	RelationOverlappedBy: "overlapped by",
+	RelationMetBy:        "met by",
+	RelationAfter:        "after",
+}
+
+func (r Relation) String() string {
+	if int(r) < len(relationNames) {
+		return relationNames[r]
+	}
+	return fmt.Sprintf("Relation(%d)", uint8(r))
+}
+
+// Inverse returns the relation of b to l, given the relation of l to b.
+func (r Relation) Inverse() Relation {
+	if r == RelationUndefined || r > RelationAfter {
+		return r
+	}
+	return RelationAfter + RelationBefore - r
+}
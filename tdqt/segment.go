@@ -0,0 +1,49 @@
+package tdqt
+
+import "github.com/chrismarget/two-dimensional-quad-tree/internal/exact"
+
+// SegmentIntersector is an optional interface which may be implemented by an
+// Object to take part in Tree.SearchSegment. Objects which don't implement it
+// are never found by segment searches.
+type SegmentIntersector interface {
+	// IntersectsSegment indicates whether the object shares any point with the
+	// closed line segment (x1,y1)<->(x2,y2).
+	IntersectsSegment(x1, y1, x2, y2 int64) bool
+}
+
+// SearchSegment returns the objects which the closed line segment
+// (x1,y1)<->(x2,y2) crosses, keyed by hash. Only nodes whose areas the segment
+// passes through are visited, and objects are found by their exact geometry
+// (see SegmentIntersector) rather than by their bounding boxes. Both tests are
+// exact, however large the coordinates. As with Search, only one object is
+// returned per hash.
+func (t *Tree) SearchSegment(x1, y1, x2, y2 int64) map[uint64]Object {
+	defer t.rLock()()
+
+	result := make(map[uint64]Object)
+	t.searchSegment(x1, y1, x2, y2, result)
+
+	return result
+}
+
+func (t *Tree) searchSegment(x1, y1, x2, y2 int64, result map[uint64]Object) {
+	if overlaps, _ := exact.SegmentOverlaps(x1, y1, x2, y2, t.area.box()); !overlaps {
+		return
+	}
+
+	for _, st := range t.subTrees {
+		if st == nil {
+			break
+		}
+		st.searchSegment(x1, y1, x2, y2, result)
+	}
+
+	t.each(func(key uint64, obj Object) {
+		if _, ok := result[key]; ok {
+			return // already found in another leaf
+		}
+		if si, ok := obj.(SegmentIntersector); ok && si.IntersectsSegment(x1, y1, x2, y2) {
+			result[key] = obj
+		}
+	})
+}
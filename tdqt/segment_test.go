@@ -0,0 +1,127 @@
+package tdqt_test
+
+import (
+	"image/color"
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/chrismarget/two-dimensional-quad-tree/objects"
+	"github.com/chrismarget/two-dimensional-quad-tree/tdqt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTree_SearchSegment(t *testing.T) {
+	everywhere := tdqt.NewRectangle(tdqt.NewLimits(0, 1000), tdqt.NewLimits(0, 1000))
+	rng := rand.New(rand.NewPCG(9, 10))
+
+	var objs []tdqt.Object
+	for range 200 {
+		x, y := rng.Int64N(990), rng.Int64N(990)
+		objs = append(objs,
+			objects.NewColorPoint(x, y, color.RGBA{}),
+			objects.NewColorLine(x, y, min(999, x+rng.Int64N(100)), max(0, y-rng.Int64N(100)), color.RGBA{}),
+			objects.NewColorRect(tdqt.NewRectangle(tdqt.NewLimits(x, x+1+rng.Int64N(10)), tdqt.NewLimits(y, y+1+rng.Int64N(10))), color.RGBA{}),
+		)
+	}
+	// Points lying exactly on the diagonal, which only an exact test finds.
+	for i := range int64(10) {
+		objs = append(objs, objects.NewColorPoint(i*97, i*97, color.RGBA{}))
+	}
+	// A polygon whose bounding box (but not whose body) the diagonal crosses.
+	polygon := objects.NewColorPolygon([]objects.Vertex{{X: 500, Y: 0}, {X: 999, Y: 0}, {X: 999, Y: 600}}, nil, color.RGBA{})
+	objs = append(objs, polygon)
+
+	segments := [][4]int64{{0, 0, 999, 999}, {0, 500, 999, 500}, {250, 0, 250, 999}, {10, 900, 900, 10}, {3, 3, 3, 3}}
+	for range 20 {
+		segments = append(segments, [4]int64{rng.Int64N(1000), rng.Int64N(1000), rng.Int64N(1000), rng.Int64N(1000)})
+	}
+
+	for _, placement := range []tdqt.PlacementMode{tdqt.PlacementDuplicate, tdqt.PlacementLoose} {
+		t.Run(placement.String(), func(t *testing.T) {
+			tree := tdqt.NewTree(everywhere, tdqt.WithMaxObjects(4), tdqt.WithPlacement(placement))
+			for _, o := range objs {
+				tree.Insert(o)
+			}
+
+			for _, s := range segments {
+				var expected []uint64
+				for _, o := range objs {
+					if o.(tdqt.SegmentIntersector).IntersectsSegment(s[0], s[1], s[2], s[3]) {
+						expected = append(expected, o.Hash())
+					}
+				}
+
+				var got []uint64
+				for hash := range tree.SearchSegment(s[0], s[1], s[2], s[3]) {
+					got = append(got, hash)
+				}
+				require.ElementsMatch(t, expected, got, "segment %v", s)
+			}
+
+			diagonal := tree.SearchSegment(0, 0, 999, 999)
+			for i := range int64(10) {
+				require.Contains(t, diagonal, objects.NewColorPoint(i*97, i*97, color.RGBA{}).Hash())
+			}
+			require.NotContains(t, diagonal, polygon.Hash())
+		})
+	}
+}
+
+func TestTree_SearchSegment_LargeCoordinates(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		placement tdqt.PlacementMode
+		seed      uint64
+	}
+
+	testCases := map[string]testCase{
+		"duplicate": {placement: tdqt.PlacementDuplicate, seed: 1},
+		"loose":     {placement: tdqt.PlacementLoose, seed: 2},
+	}
+
+	// Short lines clustered far from the origin, in nodes too small for
+	// float64 to tell their edges apart.
+	const base = 1 << 62
+	plane := tdqt.NewRectangle(tdqt.NewLimits(math.MinInt64, math.MaxInt64), tdqt.NewLimits(math.MinInt64, math.MaxInt64))
+
+	for tName, tCase := range testCases {
+		t.Run(tName, func(t *testing.T) {
+			t.Parallel()
+
+			rng := rand.New(rand.NewPCG(tCase.seed, tCase.seed))
+			tree := tdqt.NewTree(plane, tdqt.WithMaxObjects(2), tdqt.WithPlacement(tCase.placement))
+
+			var lines []objects.ColorLine
+			for range 3000 {
+				x, y := base+rng.Int64N(10_000), base+rng.Int64N(10_000)
+				l := objects.NewColorLine(x, y, x+rng.Int64N(201)-100, y+rng.Int64N(201)-100, color.RGBA{})
+				lines = append(lines, l)
+				tree.Insert(l)
+			}
+
+			var found int
+			for range 200 {
+				x1, y1 := base+rng.Int64N(10_000), base+rng.Int64N(10_000)
+				x2, y2 := base+rng.Int64N(10_000), base+rng.Int64N(10_000)
+
+				var expected []uint64
+				for _, l := range lines {
+					if l.IntersectsSegment(x1, y1, x2, y2) {
+						expected = append(expected, l.Hash())
+					}
+				}
+
+				var got []uint64
+				for hash := range tree.SearchSegment(x1, y1, x2, y2) {
+					got = append(got, hash)
+				}
+
+				require.ElementsMatch(t, expected, got)
+				found += len(got)
+			}
+			require.Greater(t, found, 200)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package tdqt
+
+import (
+	"math/big"
+	"slices"
+)
+
+// SplitStrategy chooses the coordinates at which a node is subdivided into
+// quadrants.
+type SplitStrategy interface {
+	// Split returns the x and y coordinates at which area should be divided.
+	// objs are the objects about to be redistributed among the new subtrees.
+	// Results are clamped so that neither half of an axis is narrower than
+	// the tree's min cell size.
+	Split(area Rectangle, objs []Object) (x, y int64)
+}
+
+// SplitFunc is an adapter which allows an ordinary function to be used as a
+// SplitStrategy.
+type SplitFunc func(area Rectangle, objs []Object) (x, y int64)
+
+func (f SplitFunc) Split(area Rectangle, objs []Object) (int64, int64) {
+	return f(area, objs)
+}
+
+// Anchored is an optional interface which may be implemented by an Object to
+// report a representative (x,y) coordinate. Data-driven split strategies use
+// it to learn where objects are.
+type Anchored interface {
+	Anchor() (x, y int64)
+}
+
+var (
+	_ SplitStrategy = MidpointSplit{}
+	_ SplitStrategy = MedianSplit{}
+	_ SplitStrategy = CentroidSplit{}
+)
+
+// MidpointSplit divides a node at the midpoint of each axis, as determined by
+// the tree's midpoint function (see WithMidpointFunc). It is the default.
+type MidpointSplit struct{}
+
+func (MidpointSplit) Split(area Rectangle, _ []Object) (int64, int64) {
+	return area.xRange.midpoint(), area.yRange.midpoint()
+}
+
+// MedianSplit divides a node at the median x and y of the Anchored objects
+// being redistributed. Nodes holding no Anchored objects are divided at the
+// midpoint.
+type MedianSplit struct{}
+
+func (MedianSplit) Split(area Rectangle, objs []Object) (int64, int64) {
+	xs, ys := anchors(objs)
+	if len(xs) == 0 {
+		return MidpointSplit{}.Split(area, objs)
+	}
+
+	slices.Sort(xs)
+	slices.Sort(ys)
+
+	return xs[len(xs)/2], ys[len(ys)/2]
+}
+
+// CentroidSplit divides a node at the centroid (mean x and y) of the Anchored
+// objects being redistributed. Nodes holding no Anchored objects are divided
+// at the midpoint.
+type CentroidSplit struct{}
+
+func (CentroidSplit) Split(area Rectangle, objs []Object) (int64, int64) {
+	xs, ys := anchors(objs)
+	if len(xs) == 0 {
+		return MidpointSplit{}.Split(area, objs)
+	}
+
+	return mean(xs), mean(ys)
+}
+
+// anchors collects the coordinates of the Anchored objects in objs.
+func anchors(objs []Object) ([]int64, []int64) {
+	xs := make([]int64, 0, len(objs))
+	ys := make([]int64, 0, len(objs))
+	for _, obj := range objs {
+		if a, ok := obj.(Anchored); ok {
+			x, y := a.Anchor()
+			xs = append(xs, x)
+			ys = append(ys, y)
+		}
+	}
+
+	return xs, ys
+}
+
+// mean returns the (truncated) mean of vals, which must not be empty. The sum
+// is accumulated with math/big because int64 coordinates span the full range.
+func mean(vals []int64) int64 {
+	sum := new(big.Int)
+	for _, v := range vals {
+		sum.Add(sum, big.NewInt(v))
+	}
+
+	return sum.Quo(sum, big.NewInt(int64(len(vals)))).Int64()
+}
@@ -0,0 +1,56 @@
+package tdqt
+
+// Stats describe the shape of a Tree.
+type Stats struct {
+	// Nodes is the total number of nodes, including the root.
+	Nodes int
+
+	// Leaves is the number of nodes without subtrees.
+	Leaves int
+
+	// Objects is the number of objects stored in the tree's nodes. With
+	// PlacementDuplicate, objects which span multiple leaves are counted
+	// once per leaf.
+	Objects int
+
+	// MaxDepth is the depth of the deepest node.
+	MaxDepth uint8
+
+	// DepthLimitedLeaves is the number of leaves holding more than the
+	// configured max objects because they reached the configured max depth.
+	DepthLimitedLeaves int
+}
+
+// Stats walks the Tree and returns a summary of its shape.
+func (t *Tree) Stats() Stats {
+	defer t.rLock()()
+
+	var result Stats
+	t.stats(&result)
+	return result
+}
+
+func (t *Tree) stats(s *Stats) {
+	s.Nodes++
+	s.MaxDepth = max(s.MaxDepth, t.depth)
+	s.Objects += len(t.objects)
+	for _, objs := range t.collisions {
+		s.Objects += len(objs)
+	}
+
+	if t.subTrees[0] == nil {
+		s.Leaves++
+		if t.depthLimited {
+			s.DepthLimitedLeaves++
+		}
+		return
+	}
+
+	for _, st := range t.subTrees {
+		if st == nil {
+			break
+		}
+
+		st.stats(s)
+	}
+}
@@ -1,29 +1,93 @@
 package tdqt
 
+import (
+	"errors"
+	"fmt"
+)
+
 type Tree struct {
 	area            Rectangle
 	cannotSubdivide bool
+	cfg             *config
 	depth           uint8
-	insertCallback  func(tree *Tree, depth uint8)
-	maxObjects      uint16
+	depthLimited    bool
 	objects         map[uint64]Object
+	collisions      map[uint64][]Object // distinct objects whose keys collide with one in objects
+	parent          *Tree
 	subTrees        [4]*Tree
+
+	// count is the number of distinct objects stored in this subtree, and
+	// contained is the number of those which lie wholly within area.
+	// summaries summarize the contained objects, one per Aggregator.
+	count     uint64
+	contained uint64
+	summaries []Summary
 }
 
+// Insert adds obj to the tree. An Identified object replaces any previous
+// version of itself (or, with CollisionKeep, is discarded in favor of it),
+// wherever in the tree the previous version was stored.
 func (t *Tree) Insert(obj Object) {
-	h := obj.Hash()
-	t.insert(h, obj, 0)
+	defer t.lock()()
+
+	key := t.cfg.key(obj)
+	if _, ok := obj.(Identified); ok {
+		if _, ok := t.cfg.index[key]; ok {
+			if t.cfg.collisionPolicy == CollisionKeep {
+				return
+			}
+			t.remove(key)
+		}
+	}
+
+	e := newEntry(key, obj)
+	_, contained := e.overlaps(&t.area)
+	t.insert(&e, t.depth, contained)
 }
 
+// Search returns the objects which overlap area, keyed by hash. When
+// distinct objects share a hash (see Equaler), only one of them is returned;
+// use SearchAll to retrieve all of them.
 func (t *Tree) Search(area Rectangle) map[uint64]Object {
+	defer t.rLock()()
+
 	result := make(map[uint64]Object)
 
-	t.search(area, result)
+	t.search(area, func(key uint64, obj Object) {
+		result[key] = obj
+	})
 
 	return result
 }
 
-func (t *Tree) search(area Rectangle, result map[uint64]Object) {
+// SearchAll returns every distinct object which overlaps area, including
+// objects whose hashes collide. The order of the result is unspecified.
+func (t *Tree) SearchAll(area Rectangle) []Object {
+	defer t.rLock()()
+
+	// Objects spanning several leaves are found more than once. Discard the
+	// repeats while keeping distinct objects which share a hash.
+	found := make(map[uint64][]Object)
+	t.search(area, func(key uint64, obj Object) {
+		for _, f := range found[key] {
+			if equal(f, obj) {
+				return
+			}
+		}
+		found[key] = append(found[key], obj)
+	})
+
+	var result []Object
+	for _, objs := range found {
+		result = append(result, objs...)
+	}
+
+	return result
+}
+
+// search calls fn with each object which overlaps area. Objects stored in
+// multiple nodes may be reported more than once.
+func (t *Tree) search(area Rectangle, fn func(key uint64, obj Object)) {
 	if !t.overlaps(area) {
 		return
 	}
@@ -33,161 +97,342 @@ func (t *Tree) search(area Rectangle, result map[uint64]Object) {
 			break // any nil subTree means we won't find subsequent subTrees
 		}
 
-		st.search(area, result)
+		st.search(area, fn)
 	}
 
 	for k, v := range t.objects {
-		if overlap, _ := v.Overlaps(area); overlap {
-			result[k] = v
+		if overlap, _ := objectOverlaps(v, area); overlap {
+			fn(k, v)
+		}
+	}
+
+	for k, objs := range t.collisions {
+		for _, v := range objs {
+			if overlap, _ := objectOverlaps(v, area); overlap {
+				fn(k, v)
+			}
 		}
 	}
 }
 
-func (t *Tree) createSubtrees() {
-	xMid := t.area.xRange.midpoint()
-	yMid := t.area.yRange.midpoint()
+// createSubtrees populates t.subTrees, splitting t.area at the coordinates
+// chosen by the tree's SplitStrategy. It returns false if neither axis of
+// t.area could be split.
+func (t *Tree) createSubtrees() bool {
+	objs := make([]Object, 0, len(t.objects))
+	t.each(func(_ uint64, obj Object) {
+		objs = append(objs, obj)
+	})
 
-	var cannotSplitX bool
-	if xMid == t.area.xRange.max {
-		cannotSplitX = true
-	}
+	xMid, yMid := t.cfg.splitStrategy.Split(t.area, objs)
 
-	var cannotSplitY bool
-	if yMid == t.area.yRange.max {
-		cannotSplitY = true
-	}
+	// An axis cannot be split if it's already at the minimum cell size.
+	cannotSplitX := t.area.xRange.cannotSubdivide(t.cfg.minCellSize)
+	cannotSplitY := t.area.yRange.cannotSubdivide(t.cfg.minCellSize)
 
-	var subTreeAreas []Rectangle
+	// Keep the split points far enough from the edges to honor the minimum
+	// cell size, regardless of what the strategy came up with.
+	if !cannotSplitX {
+		xMid = t.area.xRange.clampSplit(xMid, t.cfg.minCellSize)
+	}
+	if !cannotSplitY {
+		yMid = t.area.yRange.clampSplit(yMid, t.cfg.minCellSize)
+	}
 
-	// Calculate the Limits of each subtree. We don't need to worry about
-	// being unable to split both X and Y. This is handled by the tree's
-	// cannotSubdivide boolean.
-	switch {
-	case cannotSplitX:
-		subTreeAreas = []Rectangle{
-			NewRectangle(NewLimits(t.area.xRange.min, t.area.xRange.max), NewLimits(yMid, t.area.yRange.max)), // quadrant I and II
-			NewRectangle(NewLimits(t.area.xRange.min, t.area.xRange.max), NewLimits(t.area.yRange.min, yMid)), // quadrant III and IV
-		}
-	case cannotSplitY:
-		subTreeAreas = []Rectangle{ // Calculate the Limits of each subtree
-			NewRectangle(NewLimits(xMid, t.area.xRange.max), NewLimits(t.area.yRange.min, t.area.yRange.max)), // quadrant I and IV
-			NewRectangle(NewLimits(t.area.xRange.min, xMid), NewLimits(t.area.yRange.min, t.area.yRange.max)), // quadrant I and IV
-		}
-	default:
-		subTreeAreas = []Rectangle{ // Calculate the Limits of each subtree
-			NewRectangle(NewLimits(xMid, t.area.xRange.max), NewLimits(yMid, t.area.yRange.max)), // quadrant I
-			NewRectangle(NewLimits(t.area.xRange.min, xMid), NewLimits(yMid, t.area.yRange.max)), // quadrant II
-			NewRectangle(NewLimits(t.area.xRange.min, xMid), NewLimits(t.area.yRange.min, yMid)), // quadrant III
-			NewRectangle(NewLimits(xMid, t.area.xRange.max), NewLimits(t.area.yRange.min, yMid)), // quadrant IV
-		}
+	// Calculate the Limits of each subtree.
+	subTreeAreas := t.area.quadrants(xMid, yMid, !cannotSplitX, !cannotSplitY)
+	if subTreeAreas == nil {
+		return false
 	}
 
 	// Create each subtree using the calculated Limits
 	for i, sta := range subTreeAreas {
-		xMin, xMax, yMin, yMax := sta.xyMinMax()
-		t.subTrees[i] = newTree(newTreeCfg{
-			xMin:               xMin,
-			xMax:               xMax,
-			yMin:               yMin,
-			yMax:               yMax,
-			insertCallbackFunc: t.insertCallback,
-			maxObjects:         t.maxObjects,
-		})
+		t.subTrees[i] = newTree(sta, t.cfg, t.depth+1)
+		t.subTrees[i].parent = t
 	}
+
+	return true
 }
 
-func (t *Tree) SetInsertCallback(f func(tree *Tree, depth uint8)) {
-	t.insertCallback = f
+// insert places e in this subtree, and reports whether it was added rather
+// than replacing (or being discarded in favor of) an equal object.
+// contained indicates whether e lies wholly within t.area.
+func (t *Tree) insert(e *entry, depth uint8, contained bool) bool {
+	added := t.place(e, depth)
+	if added {
+		t.count++
+		if contained {
+			t.contained++
+			for _, s := range t.summaries {
+				s.Add(e.obj)
+			}
+		}
+	}
+
+	return added
 }
 
-func (t *Tree) insert(key uint64, obj Object, depth uint8) {
-	if t.cannotSubdivide {
+func (t *Tree) place(e *entry, depth uint8) bool {
+	// Trees which have been subdivided will have a non-nil subtrees at index 0
+	if t.subTrees[0] != nil {
+		return t.insertIntoSubtree(e, depth+1)
+	}
+
+	if t.cannotSubdivide || depth >= t.cfg.maxDepth {
 		// This node has been divided as far as we're going to take it.
 		// Add this point without regard for the usual capacity limit.
-		t.objects[key] = obj
-		if t.insertCallback != nil {
-			t.insertCallback(t, depth)
+		if !t.cannotSubdivide && uint16(len(t.objects)) >= t.cfg.maxObjects {
+			t.depthLimited = true // only the depth limit kept us from splitting
 		}
-		return
+		return t.store(e.key, e.obj)
 	}
 
 	// Maybe we've reached the slice capacity the tipping point?
-	if uint16(len(t.objects)) >= t.maxObjects {
-		t.subdivide(depth)
-		t.insertIntoSubtree(key, obj, depth+1)
-		return
-	}
+	if uint16(len(t.objects)) >= t.cfg.maxObjects {
+		if t.subdivide(depth) {
+			return t.insertIntoSubtree(e, depth+1)
+		}
 
-	// Trees which have been subdivided will have a non-nil subtrees at index 0
-	if t.subTrees[0] != nil {
-		t.insertIntoSubtree(key, obj, depth+1)
-		return
+		// subdivide() marked this node as a bucket; fall through and store
+		return t.store(e.key, e.obj)
 	}
 
 	// just store the point
-	t.objects[key] = obj
-	if t.insertCallback != nil {
-		t.insertCallback(t, depth)
+	return t.store(e.key, e.obj)
+}
+
+// store adds obj to this node's objects, subject to the collision policy. It
+// reports whether obj was added, rather than replacing (or being discarded in
+// favor of) an equal object.
+func (t *Tree) store(key uint64, obj Object) bool {
+	added := true
+	if stored, ok := t.objects[key]; ok {
+		switch {
+		case !equal(stored, obj):
+			var ok bool
+			if ok, added = t.storeCollision(key, obj); !ok {
+				return false
+			}
+		case t.cfg.collisionPolicy == CollisionKeep:
+			return false
+		default:
+			t.objects[key] = obj
+			added = false
+		}
+	} else {
+		t.objects[key] = obj
+	}
+
+	if t.cfg.indexed(obj) {
+		t.addToIndex(key)
+	}
+
+	if t.cfg.insertCallback != nil {
+		t.cfg.insertCallback(t, t.depth)
+	}
+
+	return added
+}
+
+// storeCollision adds obj to the objects whose key collides with an object
+// in t.objects, subject to the collision policy. It reports whether obj was
+// stored, and whether it was added rather than replacing an equal object.
+func (t *Tree) storeCollision(key uint64, obj Object) (bool, bool) {
+	objs := t.collisions[key]
+	for i, stored := range objs {
+		if equal(stored, obj) {
+			if t.cfg.collisionPolicy == CollisionKeep {
+				return false, false
+			}
+			objs[i] = obj
+			return true, false
+		}
+	}
+
+	if t.collisions == nil {
+		t.collisions = make(map[uint64][]Object)
+	}
+	t.collisions[key] = append(objs, obj)
+
+	return true, true
+}
+
+// each calls fn with each object stored at this node.
+func (t *Tree) each(fn func(key uint64, obj Object)) {
+	for k, v := range t.objects {
+		fn(k, v)
+	}
+
+	for k, objs := range t.collisions {
+		for _, v := range objs {
+			fn(k, v)
+		}
 	}
 }
 
 // insertIntoSubtree determines which subtree to use, and calls Insert() on that subtree.
-func (t *Tree) insertIntoSubtree(key uint64, obj Object, depth uint8) {
+// It reports whether e was added to any of them.
+func (t *Tree) insertIntoSubtree(e *entry, depth uint8) bool {
+	if t.cfg.placement == PlacementLoose {
+		return t.insertIntoContainingSubtree(e, depth)
+	}
+
+	var added bool
 	for _, st := range t.subTrees {
-		if overlap, fullyContained := obj.Overlaps(st.area); overlap {
-			st.insert(key, obj, depth)
+		if st == nil {
+			break
+		}
+
+		if overlap, fullyContained := e.overlaps(&st.area); overlap {
+			added = st.insert(e, depth, fullyContained) || added
 			if fullyContained {
-				return
+				break
 			}
 		}
 	}
+
+	return added
+}
+
+// insertIntoContainingSubtree hands obj to the subtree which fully contains
+// it. If there's no such subtree, obj is stored at this node.
+func (t *Tree) insertIntoContainingSubtree(e *entry, depth uint8) bool {
+	for _, st := range t.subTrees {
+		if st == nil {
+			break
+		}
+
+		if _, fullyContained := e.overlaps(&st.area); fullyContained {
+			return st.insert(e, depth, true)
+		}
+	}
+
+	return t.store(e.key, e.obj)
 }
 
 func (t *Tree) overlaps(a Rectangle) bool {
 	return t.area.Overlaps(a)
 }
 
-func (t *Tree) subdivide(depth uint8) {
+// subdivide splits the node and redistributes its objects among the new
+// subtrees. If the node's area cannot be split, it is marked cannotSubdivide
+// and false is returned.
+func (t *Tree) subdivide(depth uint8) bool {
 	// create subtrees
-	t.createSubtrees()
+	if !t.createSubtrees() {
+		t.cannotSubdivide = true
+		return false
+	}
+
+	if t.cfg.subdivideCallback != nil {
+		t.cfg.subdivideCallback(t, depth)
+	}
+
+	// The objects map is not going to be used again, except in loose mode
+	// where it holds objects which straddle the new subtrees.
+	objs, collisions := t.objects, t.collisions
+	t.each(func(k uint64, v Object) {
+		if t.cfg.indexed(v) {
+			t.dropFromIndex(k) // redistribution will index the new locations
+		}
+	})
+	t.objects, t.collisions = nil, nil
+	if t.cfg.placement == PlacementLoose {
+		t.objects = make(map[uint64]Object)
+	}
 
 	// redistribute objects among new subtrees
-	for k, v := range t.objects {
-		t.insertIntoSubtree(k, v, depth+1)
+	for k, v := range objs {
+		e := newEntry(k, v)
+		t.insertIntoSubtree(&e, depth+1)
+	}
+	for k, vs := range collisions {
+		for _, v := range vs {
+			e := newEntry(k, v)
+			t.insertIntoSubtree(&e, depth+1)
+		}
 	}
 
-	// the objects slice is not going to be used again
-	t.objects = nil
+	return true
 }
 
-func NewTree(xMin, xMax, yMin, yMax int64, maxObjects uint16) *Tree {
-	nt := newTree(newTreeCfg{
-		xMin:       xMin,
-		xMax:       xMax,
-		yMin:       yMin,
-		yMax:       yMax,
-		maxObjects: maxObjects,
-	})
-	return nt
+// lock acquires the Tree's write lock (when running in ConcurrencyLocked
+// mode) and returns the matching unlock function.
+func (t *Tree) lock() func() {
+	if t.cfg.concurrency != ConcurrencyLocked {
+		return func() {}
+	}
+
+	t.cfg.mu.Lock()
+	return t.cfg.mu.Unlock
+}
+
+// rLock acquires the Tree's read lock (when running in ConcurrencyLocked
+// mode) and returns the matching unlock function.
+func (t *Tree) rLock() func() {
+	if t.cfg.concurrency != ConcurrencyLocked {
+		return func() {}
+	}
+
+	t.cfg.mu.RLock()
+	return t.cfg.mu.RUnlock
+}
+
+// NewTreeWithOptions returns a Tree covering bounds. An error is returned if
+// bounds is empty or inverted, or if any of opts cannot be honored.
+func NewTreeWithOptions(bounds Rectangle, opts ...Option) (*Tree, error) {
+	if err := bounds.xRange.valid(); err != nil {
+		return nil, fmt.Errorf("x-axis: %w", err)
+	}
+
+	if err := bounds.yRange.valid(); err != nil {
+		return nil, fmt.Errorf("y-axis: %w", err)
+	}
+
+	if bounds.empty() {
+		return nil, fmt.Errorf("tree bounds %s are empty", bounds)
+	}
+
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		if opt == nil {
+			return nil, errors.New("nil option")
+		}
+
+		if err := opt(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	return newTree(bounds, cfg, 0), nil
 }
 
-type newTreeCfg struct {
-	xMin               int64
-	xMax               int64
-	yMin               int64
-	yMax               int64
-	insertCallbackFunc func(tree *Tree, depth uint8)
-	maxObjects         uint16
+// NewTree is a convenience wrapper around NewTreeWithOptions which panics
+// rather than returning an error.
+func NewTree(bounds Rectangle, opts ...Option) *Tree {
+	nt, err := NewTreeWithOptions(bounds, opts...)
+	if err != nil {
+		panic(err)
+	}
+
+	return nt
 }
 
-func newTree(cfg newTreeCfg) *Tree {
-	area := NewRectangle(NewLimits(cfg.xMin, cfg.xMax), NewLimits(cfg.yMin, cfg.yMax))
+func newTree(area Rectangle, cfg *config, depth uint8) *Tree {
+	area.xRange.midpointFunc = cfg.midpointFunc
+	area.yRange.midpointFunc = cfg.midpointFunc
 
-	return &Tree{
+	result := &Tree{
 		area:            area,
-		cannotSubdivide: area.cannotSubdivide(),
-		insertCallback:  cfg.insertCallbackFunc,
-		maxObjects:      cfg.maxObjects,
+		cannotSubdivide: area.cannotSubdivide(cfg.minCellSize),
+		cfg:             cfg,
+		depth:           depth,
 		objects:         make(map[uint64]Object),
 	}
+
+	for _, agg := range cfg.aggregators {
+		result.summaries = append(result.summaries, agg.NewSummary())
+	}
+
+	return result
 }
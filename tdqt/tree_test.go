@@ -6,6 +6,7 @@ import (
 	"image/color"
 	"math"
 	"math/rand/v2"
+	"sync"
 	"testing"
 	"time"
 
@@ -14,6 +15,327 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+func TestNewTreeWithOptions(t *testing.T) {
+	type testCase struct {
+		bounds tdqt.Rectangle
+		opts   []tdqt.Option
+		expErr bool
+	}
+
+	unit := tdqt.NewLimits(0, 100)
+	bounds := tdqt.NewRectangle(unit, unit)
+
+	testCases := map[string]testCase{
+		"defaults": {
+			bounds: bounds,
+		},
+		"all_options": {
+			bounds: bounds,
+			opts: []tdqt.Option{
+				tdqt.WithMaxObjects(1),
+				tdqt.WithMaxDepth(tdqt.MaxDepthLimit),
+				tdqt.WithMinCellSize(4),
+				tdqt.WithMidpointFunc(func(a, b int64) int64 { return a + (b-a)/3 }),
+				tdqt.WithInsertCallback(func(*tdqt.Tree, uint8) {}),
+				tdqt.WithSubdivideCallback(func(*tdqt.Tree, uint8) {}),
+				tdqt.WithCollisionPolicy(tdqt.CollisionKeep),
+				tdqt.WithConcurrency(tdqt.ConcurrencyLocked),
+			},
+		},
+		"zero_capacity": {
+			bounds: bounds,
+			opts:   []tdqt.Option{tdqt.WithMaxObjects(0)},
+			expErr: true,
+		},
+		"excessive_depth": {
+			bounds: bounds,
+			opts:   []tdqt.Option{tdqt.WithMaxDepth(tdqt.MaxDepthLimit + 1)},
+			expErr: true,
+		},
+		"zero_cell_size": {
+			bounds: bounds,
+			opts:   []tdqt.Option{tdqt.WithMinCellSize(0)},
+			expErr: true,
+		},
+		"nil_midpoint_func": {
+			bounds: bounds,
+			opts:   []tdqt.Option{tdqt.WithMidpointFunc(nil)},
+			expErr: true,
+		},
+		"unknown_collision_policy": {
+			bounds: bounds,
+			opts:   []tdqt.Option{tdqt.WithCollisionPolicy(255)},
+			expErr: true,
+		},
+		"unknown_concurrency_mode": {
+			bounds: bounds,
+			opts:   []tdqt.Option{tdqt.WithConcurrency(255)},
+			expErr: true,
+		},
+		"nil_option": {
+			bounds: bounds,
+			opts:   []tdqt.Option{nil},
+			expErr: true,
+		},
+		"empty_bounds": {
+			bounds: tdqt.NewRectangle(tdqt.NewLimits(5, 5), unit),
+			expErr: true,
+		},
+		"zero_value_bounds": {
+			bounds: tdqt.Rectangle{},
+			expErr: true,
+		},
+	}
+
+	for tName, tCase := range testCases {
+		t.Run(tName, func(t *testing.T) {
+			t.Parallel()
+
+			tree, err := tdqt.NewTreeWithOptions(tCase.bounds, tCase.opts...)
+			if tCase.expErr {
+				require.Error(t, err)
+				require.Nil(t, tree)
+				require.Panics(t, func() { tdqt.NewTree(tCase.bounds, tCase.opts...) })
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, tree)
+		})
+	}
+}
+
+func TestTree_OptionsPropagate(t *testing.T) {
+	var maxDepth uint8
+	var subdivisions int
+
+	tree := tdqt.NewTree(
+		tdqt.NewRectangle(tdqt.NewLimits(0, 1024), tdqt.NewLimits(0, 1024)),
+		tdqt.WithMaxObjects(1),
+		tdqt.WithMinCellSize(64),
+		tdqt.WithInsertCallback(func(tree *tdqt.Tree, depth uint8) {
+			maxDepth = max(maxDepth, depth)
+		}),
+		tdqt.WithSubdivideCallback(func(tree *tdqt.Tree, depth uint8) {
+			subdivisions++
+		}),
+	)
+
+	// Insert a run of points which all land in the bottom left cell.
+	for i := range int64(10) {
+		tree.Insert(objects.NewColorPoint(i, i, color.RGBA{}))
+	}
+
+	// 1024 -> 512 -> 256 -> 128 -> 64: subtrees at depth 4 are at the
+	// minimum cell size and must not be split further.
+	require.Equal(t, uint8(4), maxDepth)
+	require.Equal(t, 4, subdivisions)
+	require.Len(t, tree.Search(tdqt.NewRectangle(tdqt.NewLimits(0, 64), tdqt.NewLimits(0, 64))), 10)
+}
+
+func TestTree_MaxDepth(t *testing.T) {
+	bounds := tdqt.NewRectangle(tdqt.NewLimits(0, 1<<20), tdqt.NewLimits(0, 1<<20))
+
+	// Identical coordinates with distinct colors (and hashes) can never be
+	// separated by splitting. Without a depth limit they'd drive the tree
+	// down to a 1x1 cell, 20 levels deep.
+	pile := func(tree *tdqt.Tree) {
+		for i := range 100 {
+			tree.Insert(objects.NewColorPoint(5, 5, color.RGBA{R: uint8(i)}))
+		}
+	}
+
+	unlimited := tdqt.NewTree(bounds, tdqt.WithMaxObjects(4))
+	pile(unlimited)
+	stats := unlimited.Stats()
+	require.Equal(t, uint8(20), stats.MaxDepth)
+	require.Zero(t, stats.DepthLimitedLeaves)
+
+	limited := tdqt.NewTree(bounds, tdqt.WithMaxObjects(4), tdqt.WithMaxDepth(3))
+	pile(limited)
+	stats = limited.Stats()
+	require.Equal(t, uint8(3), stats.MaxDepth)
+	require.Equal(t, 1, stats.DepthLimitedLeaves)
+	require.Equal(t, 13, stats.Nodes)
+	require.Equal(t, 10, stats.Leaves)
+	require.Equal(t, 100, stats.Objects)
+	require.Len(t, limited.Search(bounds), 100)
+
+	// a max depth of zero makes the root an overflow bucket
+	flat := tdqt.NewTree(bounds, tdqt.WithMaxObjects(4), tdqt.WithMaxDepth(0))
+	pile(flat)
+	require.Equal(t, tdqt.Stats{Nodes: 1, Leaves: 1, Objects: 100, DepthLimitedLeaves: 1}, flat.Stats())
+}
+
+func TestTree_SplitStrategy(t *testing.T) {
+	bounds := tdqt.NewRectangle(tdqt.NewLimits(0, math.MaxInt64), tdqt.NewLimits(0, math.MaxInt64))
+
+	// A tight cluster of points in a vast, mostly empty space.
+	cluster := func(tree *tdqt.Tree) {
+		for i := range int64(1000) {
+			tree.Insert(objects.NewColorPoint(1000+i%37, 1000+i/37, color.RGBA{}))
+		}
+	}
+
+	strategies := map[string]tdqt.SplitStrategy{
+		"median":   tdqt.MedianSplit{},
+		"centroid": tdqt.CentroidSplit{},
+		"func": tdqt.SplitFunc(func(area tdqt.Rectangle, _ []tdqt.Object) (int64, int64) {
+			return 1018, 1013 // the middle of the cluster, more or less
+		}),
+	}
+
+	midpoint := tdqt.NewTree(bounds, tdqt.WithMaxObjects(16))
+	cluster(midpoint)
+	midpointStats := midpoint.Stats()
+
+	for name, strategy := range strategies {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			tree := tdqt.NewTree(bounds, tdqt.WithMaxObjects(16), tdqt.WithSplitStrategy(strategy))
+			cluster(tree)
+			stats := tree.Stats()
+
+			require.Less(t, stats.MaxDepth, midpointStats.MaxDepth)
+			require.Less(t, stats.Nodes, midpointStats.Nodes)
+			require.Len(t, tree.Search(bounds), 1000)
+			require.Len(t, tree.Search(tdqt.NewRectangle(tdqt.NewLimits(1000, 1010), tdqt.NewLimits(1000, 1010))), 100)
+		})
+	}
+}
+
+func TestTree_PlacementLoose(t *testing.T) {
+	bounds := tdqt.NewRectangle(tdqt.NewLimits(0, 1<<16), tdqt.NewLimits(0, 1<<16))
+
+	duplicate := tdqt.NewTree(bounds, tdqt.WithMaxObjects(8))
+	loose := tdqt.NewTree(bounds, tdqt.WithMaxObjects(8), tdqt.WithPlacement(tdqt.PlacementLoose))
+
+	records := 2000
+	for i := range records {
+		var obj tdqt.Object
+		x1, y1 := rand.Int64N(1<<16), rand.Int64N(1<<16)
+		if i%2 == 0 {
+			obj = objects.NewColorPoint(x1, y1, color.RGBA{})
+		} else {
+			x2, y2 := rand.Int64N(1<<16), rand.Int64N(1<<16) // long lines span many leaves
+			obj = objects.NewColorLine(x1, y1, x2, y2, color.RGBA{})
+		}
+		duplicate.Insert(obj)
+		loose.Insert(obj)
+	}
+
+	// every object is stored exactly once
+	require.Equal(t, records, loose.Stats().Objects)
+	require.Greater(t, duplicate.Stats().Objects, records)
+
+	for range 100 {
+		x1, y1 := rand.Int64N(1<<16), rand.Int64N(1<<16)
+		x2, y2 := rand.Int64N(1<<16), rand.Int64N(1<<16)
+		area := tdqt.NewRectangle(tdqt.NewLimits(min(x1, x2), max(x1, x2)), tdqt.NewLimits(min(y1, y2), max(y1, y2)))
+		require.Equal(t, duplicate.Search(area), loose.Search(area))
+	}
+}
+
+func TestTree_CollisionPolicy(t *testing.T) {
+	bounds := tdqt.NewRectangle(tdqt.NewLimits(0, 100), tdqt.NewLimits(0, 100))
+	first := collidingPoint{ColorPoint: objects.NewColorPoint(1, 1, color.RGBA{R: 1})}
+	second := collidingPoint{ColorPoint: objects.NewColorPoint(2, 2, color.RGBA{R: 2})}
+	everywhere := tdqt.NewRectangle(tdqt.NewLimits(0, 100), tdqt.NewLimits(0, 100))
+
+	replace := tdqt.NewTree(bounds, tdqt.WithCollisionPolicy(tdqt.CollisionReplace))
+	replace.Insert(first)
+	replace.Insert(second)
+	require.Equal(t, map[uint64]tdqt.Object{0: second}, replace.Search(everywhere))
+
+	keep := tdqt.NewTree(bounds, tdqt.WithCollisionPolicy(tdqt.CollisionKeep))
+	keep.Insert(first)
+	keep.Insert(second)
+	require.Equal(t, map[uint64]tdqt.Object{0: first}, keep.Search(everywhere))
+}
+
+func TestTree_ConcurrencyLocked(t *testing.T) {
+	tree := tdqt.NewTree(
+		tdqt.NewRectangle(tdqt.NewLimits(0, 1000), tdqt.NewLimits(0, 1000)),
+		tdqt.WithMaxObjects(4),
+		tdqt.WithConcurrency(tdqt.ConcurrencyLocked),
+	)
+	everywhere := tdqt.NewRectangle(tdqt.NewLimits(0, 1000), tdqt.NewLimits(0, 1000))
+
+	var wg sync.WaitGroup
+	for i := range int64(8) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range int64(100) {
+				tree.Insert(objects.NewColorPoint(i*100+j, j, color.RGBA{}))
+				tree.Search(everywhere)
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Len(t, tree.Search(everywhere), 800)
+}
+
+// collidingPoint is a ColorPoint whose Hash always collides, and which
+// considers every other collidingPoint equal, leaving the CollisionPolicy to
+// choose between them.
+type collidingPoint struct {
+	objects.ColorPoint
+}
+
+func (collidingPoint) Hash() uint64 { return 0 }
+
+func (collidingPoint) Equal(o tdqt.Object) bool {
+	_, ok := o.(collidingPoint)
+	return ok
+}
+
+func TestTree_Equaler(t *testing.T) {
+	everywhere := tdqt.NewRectangle(tdqt.NewLimits(0, 1000), tdqt.NewLimits(0, 1000))
+
+	// Every object collides, but ColorPoint implements Equaler, so distinct
+	// points must coexist.
+	tree := tdqt.NewTree(everywhere,
+		tdqt.WithMaxObjects(4),
+		tdqt.WithHashFunc(func(tdqt.Object) uint64 { return 0 }),
+	)
+	for i := range int64(100) {
+		tree.Insert(objects.NewColorPoint(i*10, i*10, color.RGBA{}))
+	}
+
+	// re-inserting equal points changes nothing
+	for i := range int64(100) {
+		tree.Insert(objects.NewColorPoint(i*10, i*10, color.RGBA{}))
+	}
+
+	require.Len(t, tree.SearchAll(everywhere), 100)
+	require.Len(t, tree.Search(everywhere), 1)
+	require.Equal(t, 100, tree.Stats().Objects)
+
+	found := tree.SearchAll(tdqt.NewRectangle(tdqt.NewLimits(0, 25), tdqt.NewLimits(0, 25)))
+	require.ElementsMatch(t, []tdqt.Object{
+		objects.NewColorPoint(0, 0, color.RGBA{}),
+		objects.NewColorPoint(10, 10, color.RGBA{}),
+		objects.NewColorPoint(20, 20, color.RGBA{}),
+	}, found)
+}
+
+func TestTree_SearchAll_Duplicates(t *testing.T) {
+	everywhere := tdqt.NewRectangle(tdqt.NewLimits(0, 1000), tdqt.NewLimits(0, 1000))
+	tree := tdqt.NewTree(everywhere, tdqt.WithMaxObjects(1))
+
+	// The line spans many leaves, but is found once.
+	line := objects.NewColorLine(0, 0, 999, 999, color.RGBA{})
+	tree.Insert(line)
+	for i := range int64(20) {
+		tree.Insert(objects.NewColorPoint(i*50, 999-i*50, color.RGBA{}))
+	}
+
+	require.Len(t, tree.SearchAll(everywhere), 21)
+}
+
 func TestTree_Insert_Search(t *testing.T) {
 	records := 1000 * 1000
 
@@ -31,8 +353,11 @@ func TestTree_Insert_Search(t *testing.T) {
 		return color.RGBA{R: bytes[0], G: bytes[1], B: bytes[2], A: bytes[3]}
 	}
 
-	tree := tdqt.NewTree(0, math.MaxInt64, 0, math.MaxInt64, 400)
-	tree.SetInsertCallback(insertCallback)
+	tree := tdqt.NewTree(
+		tdqt.NewRectangle(tdqt.NewLimits(0, math.MaxInt64), tdqt.NewLimits(0, math.MaxInt64)),
+		tdqt.WithMaxObjects(400),
+		tdqt.WithInsertCallback(insertCallback),
+	)
 
 	start := time.Now()
 	for i := range records {
@@ -97,7 +422,10 @@ func TestTree_Search(t *testing.T) {
 		return color.RGBA{R: bytes[0], G: bytes[1], B: bytes[2], A: bytes[3]}
 	}
 
-	tree := tdqt.NewTree(0, math.MaxInt64, 0, math.MaxInt64, 400)
+	tree := tdqt.NewTree(
+		tdqt.NewRectangle(tdqt.NewLimits(0, math.MaxInt64), tdqt.NewLimits(0, math.MaxInt64)),
+		tdqt.WithMaxObjects(400),
+	)
 
 	for i := range records {
 		randColor := randomColor()
@@ -114,7 +442,10 @@ func TestTree_Search(t *testing.T) {
 func TestSearchingForLines(t *testing.T) {
 	lineCount := 5
 
-	tree := tdqt.NewTree(math.MinInt64, math.MaxInt64, math.MinInt64, math.MaxInt64, 400)
+	tree := tdqt.NewTree(
+		tdqt.NewRectangle(tdqt.NewLimits(math.MinInt64, math.MaxInt64), tdqt.NewLimits(math.MinInt64, math.MaxInt64)),
+		tdqt.WithMaxObjects(400),
+	)
 
 	for i := range lineCount {
 		tree.Insert(objects.NewColorLine(int64(i+1), 10, int64(i+1), -10, color.RGBA{}))
@@ -152,3 +483,102 @@ func TestSearchingForLines(t *testing.T) {
 	)
 	require.Len(t, rightHalf, lineCount)
 }
+
+// unbounded hides an Object's Bounds method (if any) from the tree.
+type unbounded struct {
+	tdqt.Object
+}
+
+// millionRecords returns the same mix of random points and short lines
+// inserted by TestTree_Insert_Search, along with a large search area.
+func millionRecords() ([]tdqt.Object, tdqt.Rectangle) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	records := make([]tdqt.Object, 1000*1000)
+	for i := range records {
+		x1, y1 := rng.Int64(), rng.Int64()
+		if i%2 == 0 {
+			records[i] = objects.NewColorPoint(x1, y1, color.RGBA{})
+			continue
+		}
+
+		records[i] = objects.NewColorLine(x1, y1, x1-rng.Int64N(1000), y1-rng.Int64N(1000), color.RGBA{})
+	}
+
+	quarter := int64(math.MaxInt64 / 4)
+	area := tdqt.NewRectangle(tdqt.NewLimits(quarter, 3*quarter), tdqt.NewLimits(quarter, 3*quarter))
+
+	return records, area
+}
+
+func TestTree_Bounded(t *testing.T) {
+	records, area := millionRecords()
+	records = records[:100*1000]
+
+	bounds := tdqt.NewRectangle(tdqt.NewLimits(0, math.MaxInt64), tdqt.NewLimits(0, math.MaxInt64))
+	bounded := tdqt.NewTree(bounds, tdqt.WithMaxObjects(400))
+	plain := tdqt.NewTree(bounds, tdqt.WithMaxObjects(400))
+	for _, record := range records {
+		bounded.Insert(record)
+		plain.Insert(unbounded{record})
+	}
+
+	require.Equal(t, plain.Stats(), bounded.Stats())
+
+	found := bounded.Search(area)
+	foundPlain := plain.Search(area)
+	require.Equal(t, len(foundPlain), len(found))
+	for k := range foundPlain {
+		_, ok := found[k]
+		require.True(t, ok)
+	}
+}
+
+func benchmarkInsert(b *testing.B, wrap func(tdqt.Object) tdqt.Object) {
+	records, _ := millionRecords()
+	for i := range records {
+		records[i] = wrap(records[i])
+	}
+
+	bounds := tdqt.NewRectangle(tdqt.NewLimits(0, math.MaxInt64), tdqt.NewLimits(0, math.MaxInt64))
+
+	b.ResetTimer()
+	for range b.N {
+		tree := tdqt.NewTree(bounds, tdqt.WithMaxObjects(400))
+		for _, record := range records {
+			tree.Insert(record)
+		}
+	}
+}
+
+func benchmarkSearch(b *testing.B, wrap func(tdqt.Object) tdqt.Object) {
+	records, area := millionRecords()
+
+	tree := tdqt.NewTree(
+		tdqt.NewRectangle(tdqt.NewLimits(0, math.MaxInt64), tdqt.NewLimits(0, math.MaxInt64)),
+		tdqt.WithMaxObjects(400),
+	)
+	for _, record := range records {
+		tree.Insert(wrap(record))
+	}
+
+	b.ResetTimer()
+	for range b.N {
+		tree.Search(area)
+	}
+}
+
+func BenchmarkTree_Insert_Bounded(b *testing.B) {
+	benchmarkInsert(b, func(o tdqt.Object) tdqt.Object { return o })
+}
+
+func BenchmarkTree_Insert_Unbounded(b *testing.B) {
+	benchmarkInsert(b, func(o tdqt.Object) tdqt.Object { return unbounded{o} })
+}
+
+func BenchmarkTree_Search_Bounded(b *testing.B) {
+	benchmarkSearch(b, func(o tdqt.Object) tdqt.Object { return o })
+}
+
+func BenchmarkTree_Search_Unbounded(b *testing.B) {
+	benchmarkSearch(b, func(o tdqt.Object) tdqt.Object { return unbounded{o} })
+}